@@ -14,6 +14,7 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/wrestler094/launchpad/internal/api"
 	"github.com/wrestler094/launchpad/internal/contracts"
+	"github.com/wrestler094/launchpad/internal/ratelimit"
 	"github.com/wrestler094/launchpad/internal/services"
 	"github.com/wrestler094/launchpad/internal/storage"
 )
@@ -26,7 +27,15 @@ func main() {
 	}
 	defer db.Close()
 
-	// Run migrations
+	// Migration subcommands: migrate up | down [steps] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// Run pending migrations
 	if err := storage.RunMigrations(db); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
@@ -38,12 +47,33 @@ func main() {
 	}
 
 	// Initialize services
-	authService := services.NewAuthService()
-	tokenService := services.NewTokenService(client, db)
-	presaleService := services.NewPresaleService(client, db)
+	userService := services.NewUserService(db)
+	authService := services.NewAuthService(db, userService)
+	apiKeyService := services.NewAPIKeyService(db)
+	repos := storage.NewPostgresRepositories(db)
+	tokenService := services.NewTokenService(client, repos.Tokens)
+	presaleService := services.NewPresaleService(client, repos.Presales, repos.Tokens, repos.Participations, repos.Whitelists, repos.Vesting)
+	auditService := services.NewAuditService(db)
+	searchService := services.NewSearchService(repos.Tokens, repos.Presales)
+
+	// Initialize rate limiter (Redis when configured, so limits hold across replicas)
+	var limiter ratelimit.Store = ratelimit.NewMemoryStore()
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		redisStore, err := ratelimit.NewRedisStore(context.Background(), redisURL)
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		defer redisStore.Close()
+		limiter = redisStore
+	}
+
+	authLimit := ratelimit.Middleware(limiter, "auth", mustRateLimitRule("RATE_LIMIT_AUTH", "20/1m"), ratelimit.KeyByIP)
+	apiLimit := ratelimit.Middleware(limiter, "api", mustRateLimitRule("RATE_LIMIT_API", "300/1m"), api.RateLimitKeyByUser)
+	createLimit := ratelimit.Middleware(limiter, "create", mustRateLimitRule("RATE_LIMIT_CREATE", "10/1m"), api.RateLimitKeyByUser)
+	publicLimit := ratelimit.Middleware(limiter, "public", mustRateLimitRule("RATE_LIMIT_PUBLIC", "120/1m"), ratelimit.KeyByIP)
 
 	// Initialize API handlers
-	apiHandlers := api.NewHandlers(authService, tokenService, presaleService)
+	apiHandlers := api.NewHandlers(authService, userService, apiKeyService, tokenService, presaleService, auditService, searchService)
 
 	// Setup router
 	r := chi.NewRouter()
@@ -59,7 +89,7 @@ func main() {
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:3001"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -74,35 +104,90 @@ func main() {
 
 		// Auth routes
 		r.Route("/auth", func(r chi.Router) {
+			r.Use(authLimit)
 			r.Get("/nonce", apiHandlers.GenerateNonce)
-			r.Post("/login", apiHandlers.Login)
+			r.With(apiHandlers.Audit("auth.login")).Post("/login", apiHandlers.Login)
 			r.Post("/verify", apiHandlers.VerifyToken)
 		})
 
 		// Protected routes
 		r.Route("/", func(r chi.Router) {
 			r.Use(apiHandlers.AuthMiddleware)
+			r.Use(apiLimit)
 
 			// Token routes
 			r.Route("/token", func(r chi.Router) {
-				r.Post("/create", apiHandlers.CreateToken)
-				r.Get("/list", apiHandlers.ListTokens)
-				r.Get("/{address}", apiHandlers.GetToken)
+				r.With(apiHandlers.Audit("token.create"), apiHandlers.RequireScope(services.ScopeTokensWrite), createLimit).Post("/create", apiHandlers.CreateToken)
+				r.With(apiHandlers.RequireScope(services.ScopeTokensRead)).Get("/list", apiHandlers.ListTokens)
+				r.With(apiHandlers.RequireScope(services.ScopeTokensRead)).Get("/{address}", apiHandlers.GetToken)
 			})
 
 			// Presale routes
 			r.Route("/presale", func(r chi.Router) {
-				r.Post("/create", apiHandlers.CreatePresale)
-				r.Get("/list", apiHandlers.ListPresales)
-				r.Get("/{id}", apiHandlers.GetPresale)
-				r.Post("/{id}/participate", apiHandlers.ParticipateInPresale)
+				r.With(apiHandlers.Audit("presale.create"), apiHandlers.RequireScope(services.ScopePresalesWrite), createLimit).Post("/create", apiHandlers.CreatePresale)
+				r.With(apiHandlers.RequireScope(services.ScopePresalesRead)).Get("/list", apiHandlers.ListPresales)
+				r.With(apiHandlers.RequireScope(services.ScopePresalesRead)).Get("/{id}", apiHandlers.GetPresale)
+				r.With(apiHandlers.RequireScope(services.ScopePresalesRead)).Get("/{id}/contributors", apiHandlers.ListContributors)
+				r.With(apiHandlers.RequireScope(services.ScopePresalesRead)).Get("/{id}/status", apiHandlers.GetPresaleStatus)
+				r.With(apiHandlers.Audit("presale.status"), apiHandlers.RequireScope(services.ScopePresalesWrite)).Post("/{id}/status", apiHandlers.TransitionPresale)
+				r.With(apiHandlers.Audit("presale.whitelist"), apiHandlers.RequireScope(services.ScopePresalesWrite)).Put("/{id}/whitelist", apiHandlers.UploadWhitelist)
+				r.With(apiHandlers.Audit("presale.participate"), apiHandlers.RequireScope(services.ScopePresalesWrite), createLimit).Post("/{id}/participate", apiHandlers.ParticipateInPresale)
+				r.With(apiHandlers.RequireScope(services.ScopePresalesRead)).Get("/{id}/vesting", apiHandlers.GetPresaleVesting)
+				r.With(apiHandlers.Audit("presale.claim"), apiHandlers.RequireScope(services.ScopePresalesWrite)).Post("/{id}/claim", apiHandlers.ClaimVestedTokens)
+			})
+
+			// Profile routes (wallet sessions only)
+			r.Route("/me", func(r chi.Router) {
+				r.Use(apiHandlers.RequireWallet)
+				r.Get("/", apiHandlers.GetMe)
+				r.With(apiHandlers.Audit("profile.update")).Put("/", apiHandlers.UpdateMe)
+				r.Get("/dashboard", apiHandlers.GetDashboard)
+				r.Get("/participations", apiHandlers.ListParticipations)
+				r.Get("/wallets", apiHandlers.ListWallets)
+				r.With(apiHandlers.Audit("wallet.link")).Post("/wallets", apiHandlers.LinkWallet)
+				r.With(apiHandlers.Audit("wallet.unlink")).Delete("/wallets/{address}", apiHandlers.UnlinkWallet)
+				r.Get("/sessions", apiHandlers.ListSessions)
+				r.With(apiHandlers.Audit("session.revoke_all")).Delete("/sessions", apiHandlers.RevokeAllSessions)
+				r.With(apiHandlers.Audit("session.revoke")).Delete("/sessions/{id}", apiHandlers.RevokeSession)
+			})
+
+			// API key routes (wallet sessions only)
+			r.Route("/keys", func(r chi.Router) {
+				r.Use(apiHandlers.RequireWallet)
+				r.With(apiHandlers.Audit("apikey.create")).Post("/", apiHandlers.CreateAPIKey)
+				r.Get("/", apiHandlers.ListAPIKeys)
+				r.With(apiHandlers.Audit("apikey.revoke")).Delete("/{id}", apiHandlers.RevokeAPIKey)
+			})
+
+			// Admin routes
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(apiHandlers.RequireWallet)
+
+				r.Route("/users", func(r chi.Router) {
+					r.Use(apiHandlers.RequirePermission(services.PermissionManageRoles))
+					r.Get("/", apiHandlers.ListUsers)
+					r.With(apiHandlers.Audit("user.role.update")).Put("/{address}/role", apiHandlers.SetUserRole)
+				})
+
+				r.With(apiHandlers.RequirePermission(services.PermissionViewAudit)).Get("/audit", apiHandlers.ListAuditEvents)
 			})
 		})
 
 		// Public presale routes (for landing pages)
 		r.Route("/public/presale", func(r chi.Router) {
+			r.Use(publicLimit)
 			r.Get("/{id}", apiHandlers.GetPublicPresale)
+			r.Get("/{id}/quote", apiHandlers.QuotePresale)
+			r.Get("/{id}/proof/{address}", apiHandlers.GetWhitelistProof)
+			r.Get("/{id}/vesting/{address}", apiHandlers.GetVestingPosition)
+		})
+
+		r.Route("/public/presales", func(r chi.Router) {
+			r.Use(publicLimit)
+			r.Get("/", apiHandlers.ListPublicPresales)
 		})
+
+		r.With(publicLimit).Get("/public/search", apiHandlers.Search)
 	})
 
 	// Server configuration
@@ -139,4 +224,13 @@ func main() {
 	}
 
 	log.Println("Server exited")
+}
+
+// mustRateLimitRule reads a rate limit rule such as "60/1m" from the environment
+func mustRateLimitRule(key, defaultValue string) ratelimit.Rule {
+	rule, err := ratelimit.RuleFromEnv(key, defaultValue)
+	if err != nil {
+		log.Fatalf("Invalid rate limit configuration: %v", err)
+	}
+	return rule
 }
\ No newline at end of file
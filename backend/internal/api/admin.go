@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/services"
+)
+
+// ListUsers handles listing users and their roles
+func (h *Handlers) ListUsers(w http.ResponseWriter, r *http.Request) {
+	role := r.URL.Query().Get("role")
+
+	users, err := h.userService.ListUsers(role)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Users retrieved", users)
+}
+
+// SetUserRole handles changing a user's role
+func (h *Handlers) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	address := chi.URLParam(r, "address")
+	if address == "" {
+		respondError(w, http.StatusBadRequest, "User address is required")
+		return
+	}
+
+	var req services.SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.userService.SetRole(userAddress, address, &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "User role updated", user)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/services"
+)
+
+// CreateAPIKey handles API key creation
+func (h *Handlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req services.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.apiKeyService.CreateAPIKey(userAddress, &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "API key created successfully", response)
+}
+
+// ListAPIKeys handles listing API keys for a user
+func (h *Handlers) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	apiKeys, err := h.apiKeyService.ListAPIKeys(userAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "API keys retrieved", apiKeys)
+}
+
+// RevokeAPIKey handles revoking an API key
+func (h *Handlers) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(userAddress, id); err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondSuccess(w, "API key revoked", nil)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/wrestler094/launchpad/internal/services"
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+// maxAuditedBody bounds the request bodies read by the audit middleware
+const maxAuditedBody = 1 << 20
+
+// auditEntry carries audit details a handler learns while serving a request
+type auditEntry struct {
+	actor string
+}
+
+// setAuditActor records the actor of an unauthenticated request, such as a
+// login attempt, for the audit middleware
+func setAuditActor(ctx context.Context, address string) {
+	if entry, ok := ctx.Value(auditEntryKey).(*auditEntry); ok {
+		entry.actor = address
+	}
+}
+
+// Audit is a middleware that appends every request to a route to the audit
+// log, with the outcome derived from the response status. It must run after
+// AuthMiddleware so the actor is known.
+func (h *Handlers) Audit(action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAuditedBody))
+			if err != nil {
+				respondError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			entry := &auditEntry{}
+			ctx := context.WithValue(r.Context(), auditEntryKey, entry)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			payloadHash := sha256.Sum256(body)
+			event := &storage.AuditEvent{
+				Action:       action,
+				ActorAddress: getUserFromContext(r.Context()),
+				RequestID:    middleware.GetReqID(r.Context()),
+				IP:           r.RemoteAddr,
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				PayloadHash:  hex.EncodeToString(payloadHash[:]),
+				Outcome:      services.AuditOutcomeSuccess,
+				StatusCode:   ww.Status(),
+			}
+
+			if event.ActorAddress == "" {
+				event.ActorAddress = entry.actor
+			}
+
+			if apiKey := getAPIKeyFromContext(r.Context()); apiKey != nil {
+				event.APIKeyID = &apiKey.ID
+			}
+
+			if host, _, err := net.SplitHostPort(event.IP); err == nil {
+				event.IP = host
+			}
+
+			if event.StatusCode == 0 {
+				event.StatusCode = http.StatusOK
+			}
+
+			if event.StatusCode >= http.StatusBadRequest {
+				event.Outcome = services.AuditOutcomeFailure
+			}
+
+			if err := h.auditService.Record(event); err != nil {
+				log.Printf("Failed to record audit event %s (request %s): %v", action, event.RequestID, err)
+			}
+		})
+	}
+}
+
+// ListAuditEvents handles querying the audit log
+func (h *Handlers) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.AuditFilter{
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		Outcome:   query.Get("outcome"),
+		RequestID: query.Get("request_id"),
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+		BeforeID:  query.Get("before_id"),
+		Limit:     query.Get("limit"),
+	}
+
+	events, err := h.auditService.ListEvents(filter)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Audit events retrieved", events)
+}
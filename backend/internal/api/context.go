@@ -2,11 +2,18 @@ package api
 
 import (
 	"context"
+
+	"github.com/wrestler094/launchpad/internal/storage"
 )
 
 type contextKey string
 
-const userAddressKey contextKey = "user_address"
+const (
+	userAddressKey contextKey = "user_address"
+	apiKeyKey      contextKey = "api_key"
+	sessionIDKey   contextKey = "session_id"
+	auditEntryKey  contextKey = "audit_entry"
+)
 
 // addUserToContext adds a user address to the request context
 func addUserToContext(ctx context.Context, address string) context.Context {
@@ -19,4 +26,31 @@ func getUserFromContext(ctx context.Context) string {
 		return address
 	}
 	return ""
-}
\ No newline at end of file
+}
+
+// addAPIKeyToContext adds the API key that authenticated the request to the context
+func addAPIKeyToContext(ctx context.Context, apiKey *storage.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey, apiKey)
+}
+
+// getAPIKeyFromContext gets the API key from the request context,
+// or nil when the request was authenticated with a JWT
+func getAPIKeyFromContext(ctx context.Context) *storage.APIKey {
+	if apiKey, ok := ctx.Value(apiKeyKey).(*storage.APIKey); ok {
+		return apiKey
+	}
+	return nil
+}
+
+// addSessionToContext adds the session ID of a JWT to the request context
+func addSessionToContext(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// getSessionFromContext gets the session ID from the request context
+func getSessionFromContext(ctx context.Context) string {
+	if sessionID, ok := ctx.Value(sessionIDKey).(string); ok {
+		return sessionID
+	}
+	return ""
+}
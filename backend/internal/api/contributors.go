@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/wrestler094/launchpad/internal/services"
+)
+
+// ListContributors handles listing a presale's contributors and leaderboard.
+// Only the presale creator, from any of their linked wallets, may see it.
+func (h *Handlers) ListContributors(w http.ResponseWriter, r *http.Request) {
+	presale, ok := h.creatorPresale(w, r, "Only the presale creator can view contributors")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	req := &services.ListContributorsRequest{
+		Sort:  query.Get("sort"),
+		Order: query.Get("order"),
+		Limit: query.Get("limit"),
+	}
+
+	contributors, err := h.presaleService.ListContributors(presale.ID, req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data := map[string]interface{}{
+		"presale_id":   presale.ID,
+		"raised":       presale.Raised,
+		"contributors": presale.Contributors,
+		"leaderboard":  contributors,
+	}
+
+	respondSuccess(w, "Contributors retrieved", data)
+}
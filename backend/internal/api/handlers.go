@@ -2,19 +2,27 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/ratelimit"
 	"github.com/wrestler094/launchpad/internal/services"
+	"github.com/wrestler094/launchpad/internal/storage"
 )
 
 // Handlers contains all HTTP handlers
 type Handlers struct {
 	authService    *services.AuthService
+	userService    *services.UserService
+	apiKeyService  *services.APIKeyService
 	tokenService   *services.TokenService
 	presaleService *services.PresaleService
+	auditService   *services.AuditService
+	searchService  *services.SearchService
 }
 
 // ErrorResponse represents an error response
@@ -24,16 +32,27 @@ type ErrorResponse struct {
 
 // SuccessResponse represents a success response
 type SuccessResponse struct {
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination carries the cursor of the next page of a list response
+type Pagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
 }
 
 // NewHandlers creates new API handlers
-func NewHandlers(authService *services.AuthService, tokenService *services.TokenService, presaleService *services.PresaleService) *Handlers {
+func NewHandlers(authService *services.AuthService, userService *services.UserService, apiKeyService *services.APIKeyService, tokenService *services.TokenService, presaleService *services.PresaleService, auditService *services.AuditService, searchService *services.SearchService) *Handlers {
 	return &Handlers{
 		authService:    authService,
+		userService:    userService,
+		apiKeyService:  apiKeyService,
 		tokenService:   tokenService,
 		presaleService: presaleService,
+		auditService:   auditService,
+		searchService:  searchService,
 	}
 }
 
@@ -54,6 +73,25 @@ func respondSuccess(w http.ResponseWriter, message string, data interface{}) {
 	respondJSON(w, http.StatusOK, SuccessResponse{Message: message, Data: data})
 }
 
+// respondPage sends one page of a list with its next-page cursor
+func respondPage(w http.ResponseWriter, message string, data interface{}, nextCursor string) {
+	respondJSON(w, http.StatusOK, SuccessResponse{
+		Message:    message,
+		Data:       data,
+		Pagination: &Pagination{NextCursor: nextCursor, HasMore: nextCursor != ""},
+	})
+}
+
+// pageRequestFromQuery reads the common paging query parameters
+func pageRequestFromQuery(query url.Values) services.PageRequest {
+	return services.PageRequest{
+		Sort:   query.Get("sort"),
+		Order:  query.Get("order"),
+		Cursor: query.Get("cursor"),
+		Limit:  query.Get("limit"),
+	}
+}
+
 // GenerateNonce generates a nonce for MetaMask authentication
 func (h *Handlers) GenerateNonce(w http.ResponseWriter, r *http.Request) {
 	address := r.URL.Query().Get("address")
@@ -79,8 +117,19 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	setAuditActor(r.Context(), req.Address)
+
+	info := services.SessionInfo{
+		UserAgent:  r.UserAgent(),
+		RemoteAddr: r.RemoteAddr,
+	}
+
+	response, err := h.authService.Login(&req, info)
 	if err != nil {
+		if errors.Is(err, services.ErrMalformedSignature) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusUnauthorized, err.Error())
 		return
 	}
@@ -106,7 +155,8 @@ func (h *Handlers) VerifyToken(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, "Token is valid", claims)
 }
 
-// AuthMiddleware is a middleware for authenticating requests
+// AuthMiddleware is a middleware for authenticating requests.
+// It accepts either a JWT or an API key as the bearer credential.
 func (h *Handlers) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -116,21 +166,102 @@ func (h *Handlers) AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := h.authService.VerifyToken(tokenString)
-		if err != nil {
-			respondError(w, http.StatusUnauthorized, "Invalid token")
-			return
+		ctx := r.Context()
+
+		if services.IsAPIKey(tokenString) {
+			apiKey, err := h.apiKeyService.Authenticate(tokenString)
+			if err != nil {
+				respondError(w, http.StatusUnauthorized, "Invalid API key")
+				return
+			}
+
+			// Add key owner and key to request context
+			ctx = addUserToContext(ctx, apiKey.OwnerAddress)
+			ctx = addAPIKeyToContext(ctx, apiKey)
+		} else {
+			claims, err := h.authService.VerifyToken(tokenString)
+			if err != nil {
+				respondError(w, http.StatusUnauthorized, "Invalid token")
+				return
+			}
+
+			// Add user address and session to request context
+			ctx = addUserToContext(ctx, claims.Address)
+			ctx = addSessionToContext(ctx, claims.ID)
 		}
 
-		// Add user address to request context
-		ctx := r.Context()
-		ctx = addUserToContext(ctx, claims.Address)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
 	})
 }
 
+// RateLimitKeyByUser keys rate limits by authenticated address,
+// falling back to the client IP for anonymous requests
+func RateLimitKeyByUser(r *http.Request) string {
+	if address := getUserFromContext(r.Context()); address != "" {
+		return "address:" + address
+	}
+	return ratelimit.KeyByIP(r)
+}
+
+// RequireScope is a middleware that rejects API keys lacking a scope.
+// Requests authenticated with a JWT have every scope.
+func (h *Handlers) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := getAPIKeyFromContext(r.Context())
+			if apiKey != nil && !services.HasScope(apiKey, scope) {
+				respondError(w, http.StatusForbidden, "API key is missing scope "+scope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermission is a middleware for route-level permission checks.
+// The role claim in the JWT is informational only; the current role is
+// read from the users table so that role changes apply immediately.
+func (h *Handlers) RequirePermission(permission services.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAddress := getUserFromContext(r.Context())
+			if userAddress == "" {
+				respondError(w, http.StatusUnauthorized, "User not authenticated")
+				return
+			}
+
+			allowed, err := h.userService.HasPermission(userAddress, permission)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to check permissions")
+				return
+			}
+
+			if !allowed {
+				respondError(w, http.StatusForbidden, "Insufficient permissions")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireWallet is a middleware that only admits requests authenticated
+// with a wallet-signed JWT, so API keys cannot manage credentials
+func (h *Handlers) RequireWallet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if getAPIKeyFromContext(r.Context()) != nil {
+			respondError(w, http.StatusForbidden, "This endpoint requires wallet authentication")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // CreateToken handles token creation
 func (h *Handlers) CreateToken(w http.ResponseWriter, r *http.Request) {
 	userAddress := getUserFromContext(r.Context())
@@ -171,7 +302,7 @@ func (h *Handlers) GetToken(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, "Token retrieved", token)
 }
 
-// ListTokens handles listing tokens for a user
+// ListTokens handles listing tokens across a user's linked wallets
 func (h *Handlers) ListTokens(w http.ResponseWriter, r *http.Request) {
 	userAddress := getUserFromContext(r.Context())
 	if userAddress == "" {
@@ -179,13 +310,26 @@ func (h *Handlers) ListTokens(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.tokenService.ListTokens(userAddress)
+	addresses, err := h.userService.AccountAddresses(userAddress)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	respondSuccess(w, "Tokens retrieved", tokens)
+	query := r.URL.Query()
+	req := &services.ListTokensRequest{
+		PageRequest: pageRequestFromQuery(query),
+		CreatedFrom: query.Get("created_from"),
+		CreatedTo:   query.Get("created_to"),
+	}
+
+	tokens, nextCursor, err := h.tokenService.ListTokens(addresses, req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPage(w, "Tokens retrieved", tokens, nextCursor)
 }
 
 // CreatePresale handles presale creation
@@ -226,6 +370,19 @@ func (h *Handlers) GetPresale(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Drafts are visible to their creator only, from any linked wallet
+	if presale.Status == storage.PresaleStatusDraft {
+		addresses, err := h.userService.AccountAddresses(getUserFromContext(r.Context()))
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !isCreator(addresses, presale) {
+			respondError(w, http.StatusNotFound, services.ErrPresaleNotFound.Error())
+			return
+		}
+	}
+
 	respondSuccess(w, "Presale retrieved", presale)
 }
 
@@ -238,7 +395,7 @@ func (h *Handlers) GetPublicPresale(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	presale, err := h.presaleService.GetPresale(id)
+	presale, err := h.presaleService.GetPublicPresale(id)
 	if err != nil {
 		respondError(w, http.StatusNotFound, err.Error())
 		return
@@ -251,15 +408,40 @@ func (h *Handlers) GetPublicPresale(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Get creator profile so investors see who is behind the launch
+	creator, err := h.userService.GetPublicProfile(presale.CreatorAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get creator profile")
+		return
+	}
+
 	data := map[string]interface{}{
 		"presale": presale,
 		"token":   token,
+		"creator": creator,
 	}
 
 	respondSuccess(w, "Presale info retrieved", data)
 }
 
-// ListPresales handles listing presales for a user
+// ListPublicPresales handles the public presale discovery feed
+func (h *Handlers) ListPublicPresales(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &services.ListPublicPresalesRequest{
+		PageRequest: pageRequestFromQuery(query),
+		Category:    query.Get("category"),
+	}
+
+	presales, nextCursor, err := h.presaleService.ListPublicPresales(req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPage(w, "Presales retrieved", presales, nextCursor)
+}
+
+// ListPresales handles listing presales across a user's linked wallets
 func (h *Handlers) ListPresales(w http.ResponseWriter, r *http.Request) {
 	userAddress := getUserFromContext(r.Context())
 	if userAddress == "" {
@@ -267,13 +449,30 @@ func (h *Handlers) ListPresales(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	presales, err := h.presaleService.ListPresales(userAddress)
+	addresses, err := h.userService.AccountAddresses(userAddress)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	respondSuccess(w, "Presales retrieved", presales)
+	query := r.URL.Query()
+	req := &services.ListPresalesRequest{
+		PageRequest:  pageRequestFromQuery(query),
+		Status:       query.Get("status"),
+		TokenAddress: query.Get("token_address"),
+		CreatedFrom:  query.Get("created_from"),
+		CreatedTo:    query.Get("created_to"),
+		DeadlineFrom: query.Get("deadline_from"),
+		DeadlineTo:   query.Get("deadline_to"),
+	}
+
+	presales, nextCursor, err := h.presaleService.ListPresales(addresses, req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPage(w, "Presales retrieved", presales, nextCursor)
 }
 
 // ParticipateInPresale handles presale participation
@@ -299,6 +498,14 @@ func (h *Handlers) ParticipateInPresale(w http.ResponseWriter, r *http.Request)
 
 	response, err := h.presaleService.ParticipateInPresale(id, userAddress, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrHardCapReached) || errors.Is(err, services.ErrExceedsCapacity) || errors.Is(err, services.ErrAllocationExceeded) || errors.Is(err, services.ErrMaxContributionExceeded) || errors.Is(err, services.ErrRoundCapReached) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrNotWhitelisted) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
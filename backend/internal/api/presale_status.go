@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/services"
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+// GetPresaleStatus handles getting a presale's status, the statuses it can
+// move to and its status history. Only the presale creator may see it.
+func (h *Handlers) GetPresaleStatus(w http.ResponseWriter, r *http.Request) {
+	presale, ok := h.creatorPresale(w, r, "Only the presale creator can view its status history")
+	if !ok {
+		return
+	}
+
+	status, err := h.presaleService.GetPresaleStatus(presale.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Presale status retrieved", status)
+}
+
+// TransitionPresale handles moving a presale to a new status. Only the
+// presale creator may change it.
+func (h *Handlers) TransitionPresale(w http.ResponseWriter, r *http.Request) {
+	presale, ok := h.creatorPresale(w, r, "Only the presale creator can change its status")
+	if !ok {
+		return
+	}
+
+	var req services.TransitionPresaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	updated, err := h.presaleService.TransitionPresale(presale.ID, getUserFromContext(r.Context()), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTransition) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Presale status updated", updated)
+}
+
+// creatorPresale loads the presale of the request and checks the user is its
+// creator, from any of their linked wallets. It responds with an error and
+// returns false otherwise.
+func (h *Handlers) creatorPresale(w http.ResponseWriter, r *http.Request, forbidden string) (*storage.Presale, bool) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid presale ID")
+		return nil, false
+	}
+
+	presale, err := h.presaleService.GetPresale(id)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+
+	addresses, err := h.userService.AccountAddresses(userAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if isCreator(addresses, presale) {
+		return presale, true
+	}
+
+	respondError(w, http.StatusForbidden, forbidden)
+	return nil, false
+}
+
+// isCreator reports whether one of an account's addresses created the presale
+func isCreator(addresses []string, presale *storage.Presale) bool {
+	for _, address := range addresses {
+		if strings.EqualFold(address, presale.CreatorAddress) {
+			return true
+		}
+	}
+	return false
+}
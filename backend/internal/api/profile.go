@@ -0,0 +1,250 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/services"
+)
+
+// GetMe handles getting the authenticated user's profile
+func (h *Handlers) GetMe(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	user, err := h.userService.GetProfile(userAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Profile retrieved", user)
+}
+
+// UpdateMe handles updating the authenticated user's profile
+func (h *Handlers) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req services.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(userAddress, &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Profile updated", user)
+}
+
+// ListWallets handles listing the wallets linked to the user's account
+func (h *Handlers) ListWallets(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	wallets, err := h.userService.ListWallets(userAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Wallets retrieved", wallets)
+}
+
+// LinkWallet handles linking another wallet to the user's account
+func (h *Handlers) LinkWallet(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req services.LinkWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.authService.LinkWallet(userAddress, &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Wallet linked", user)
+}
+
+// UnlinkWallet handles detaching a linked wallet from the user's account
+func (h *Handlers) UnlinkWallet(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	address := chi.URLParam(r, "address")
+	if address == "" {
+		respondError(w, http.StatusBadRequest, "Wallet address is required")
+		return
+	}
+
+	if err := h.userService.UnlinkWallet(userAddress, address); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Wallet unlinked", nil)
+}
+
+// GetDashboard handles getting tokens, presales and participations across
+// every wallet linked to the user's account
+func (h *Handlers) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	profile, err := h.userService.GetProfile(userAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	addresses, err := h.userService.AccountAddresses(userAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tokens, tokensCursor, err := h.tokenService.ListTokens(addresses, &services.ListTokensRequest{})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	presales, presalesCursor, err := h.presaleService.ListPresales(addresses, &services.ListPresalesRequest{})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	participations, err := h.presaleService.ListParticipations(addresses)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := map[string]interface{}{
+		"account_id":     profile.AccountID,
+		"wallets":        addresses,
+		"tokens":         tokens,
+		"presales":       presales,
+		"participations": participations,
+		// Further pages come from /api/token/list and /api/presale/list
+		"tokens_next_cursor":   tokensCursor,
+		"presales_next_cursor": presalesCursor,
+	}
+
+	respondSuccess(w, "Dashboard retrieved", data)
+}
+
+// ListSessions handles listing the user's active sessions
+func (h *Handlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(userAddress, getSessionFromContext(r.Context()))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Sessions retrieved", sessions)
+}
+
+// RevokeSession handles revoking one of the user's sessions
+func (h *Handlers) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	sessionID := chi.URLParam(r, "id")
+	if sessionID == "" {
+		respondError(w, http.StatusBadRequest, "Session ID is required")
+		return
+	}
+
+	if err := h.authService.RevokeSession(userAddress, sessionID); err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Session revoked", nil)
+}
+
+// RevokeAllSessions handles revoking all of the user's sessions.
+// With ?keep_current=true the session making the request stays active.
+func (h *Handlers) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	keepSessionID := ""
+	if r.URL.Query().Get("keep_current") == "true" {
+		keepSessionID = getSessionFromContext(r.Context())
+	}
+
+	revoked, err := h.authService.RevokeAllSessions(userAddress, keepSessionID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Sessions revoked", map[string]int64{"revoked": revoked})
+}
+
+// ListParticipations handles the portfolio of presales joined from any of
+// the user's linked wallets
+func (h *Handlers) ListParticipations(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	addresses, err := h.userService.AccountAddresses(userAddress)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	portfolio, err := h.presaleService.GetPortfolio(addresses)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Participations retrieved", portfolio)
+}
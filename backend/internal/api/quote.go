@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/services"
+)
+
+// QuotePresale handles quoting a contribution to a presale: the tokens it
+// buys, the remaining hard-cap capacity and the gas of the buyTokens call
+func (h *Handlers) QuotePresale(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid presale ID")
+		return
+	}
+
+	query := r.URL.Query()
+	quote, err := h.presaleService.QuoteParticipation(id, query.Get("amount"), query.Get("from"))
+	if err != nil {
+		if errors.Is(err, services.ErrPresaleNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrNotWhitelisted) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Quote calculated", quote)
+}
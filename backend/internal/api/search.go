@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+)
+
+// Search handles searching tokens and presales by name, ticker or address
+func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	results, err := h.searchService.Search(query.Get("q"), query.Get("limit"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Search results retrieved", results)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/services"
+)
+
+// GetPresaleVesting handles getting the vesting schedule of a presale with
+// every participant's position. Only the presale creator may view it.
+func (h *Handlers) GetPresaleVesting(w http.ResponseWriter, r *http.Request) {
+	presale, ok := h.creatorPresale(w, r, "Only the presale creator can view its vesting")
+	if !ok {
+		return
+	}
+
+	vesting, err := h.presaleService.GetPresaleVesting(presale.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrPresaleNotFound) || errors.Is(err, services.ErrPresaleNotVesting) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get vesting")
+		return
+	}
+
+	respondSuccess(w, "Vesting retrieved", vesting)
+}
+
+// GetVestingPosition handles getting an address's vested, claimable and
+// claimed tokens of a vesting presale
+func (h *Handlers) GetVestingPosition(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid presale ID")
+		return
+	}
+
+	position, err := h.presaleService.GetVestingPosition(id, chi.URLParam(r, "address"))
+	if err != nil {
+		if errors.Is(err, services.ErrPresaleNotFound) || errors.Is(err, services.ErrPresaleNotVesting) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Vesting position retrieved", position)
+}
+
+// ClaimVestedTokens handles recording a claim of the caller's vested tokens
+func (h *Handlers) ClaimVestedTokens(w http.ResponseWriter, r *http.Request) {
+	userAddress := getUserFromContext(r.Context())
+	if userAddress == "" {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid presale ID")
+		return
+	}
+
+	var req services.ClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.presaleService.ClaimVestedTokens(id, userAddress, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrPresaleNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrVestingNotStarted) || errors.Is(err, services.ErrNothingToClaim) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Claim recorded", response)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wrestler094/launchpad/internal/services"
+)
+
+// UploadWhitelist handles uploading the whitelist of a whitelisted presale.
+// Only the presale creator may upload it.
+func (h *Handlers) UploadWhitelist(w http.ResponseWriter, r *http.Request) {
+	presale, ok := h.creatorPresale(w, r, "Only the presale creator can upload its whitelist")
+	if !ok {
+		return
+	}
+
+	var req services.UploadWhitelistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.presaleService.UploadWhitelist(presale.ID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrWhitelistLocked) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Whitelist uploaded", response)
+}
+
+// GetWhitelistProof handles getting the Merkle proof an address passes to a
+// whitelisted presale contract
+func (h *Handlers) GetWhitelistProof(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid presale ID")
+		return
+	}
+
+	proof, err := h.presaleService.GetWhitelistProof(id, chi.URLParam(r, "address"))
+	if err != nil {
+		if errors.Is(err, services.ErrPresaleNotFound) || errors.Is(err, services.ErrPresaleNotWhitelisted) || errors.Is(err, services.ErrNotWhitelisted) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Whitelist proof retrieved", proof)
+}
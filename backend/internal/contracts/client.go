@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -90,4 +93,112 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// buyTokensSelector is the calldata of Presale.buyTokens()
+var buyTokensSelector = crypto.Keccak256([]byte("buyTokens()"))[:4]
+
+// whitelistBuyTokensSelector is the selector of
+// WhitelistPresale.buyTokens(uint256,bytes32[])
+var whitelistBuyTokensSelector = crypto.Keccak256([]byte("buyTokens(uint256,bytes32[])"))[:4]
+
+// tokenBuyTokensSelector is the selector of ERC20PaymentPresale.buyTokens(uint256)
+var tokenBuyTokensSelector = crypto.Keccak256([]byte("buyTokens(uint256)"))[:4]
+
+// erc20MetadataABI holds the optional ERC-20 metadata getters
+const erc20MetadataABI = `[
+	{"name":"symbol","type":"function","stateMutability":"view","inputs":[],"outputs":[{"type":"string"}]},
+	{"name":"decimals","type":"function","stateMutability":"view","inputs":[],"outputs":[{"type":"uint8"}]}
+]`
+
+// EstimateBuyTokensGas estimates the gas of a buyTokens call sending value wei
+// to a presale contract
+func (c *Client) EstimateBuyTokensGas(presale, from common.Address, value *big.Int) (uint64, error) {
+	return c.estimateGas(presale, from, value, buyTokensSelector)
+}
+
+// EstimateWhitelistBuyTokensGas estimates the gas of a buyTokens call to a
+// whitelist presale contract, proving from's allocation with proof
+func (c *Client) EstimateWhitelistBuyTokensGas(presale, from common.Address, value, allocation *big.Int, proof []common.Hash) (uint64, error) {
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	proofType, _ := abi.NewType("bytes32[]", "", nil)
+
+	siblings := make([][32]byte, len(proof))
+	for i, hash := range proof {
+		siblings[i] = hash
+	}
+
+	args, err := abi.Arguments{{Type: uint256Type}, {Type: proofType}}.Pack(allocation, siblings)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode proof: %w", err)
+	}
+
+	return c.estimateGas(presale, from, value, append(append([]byte{}, whitelistBuyTokensSelector...), args...))
+}
+
+// EstimateTokenBuyTokensGas estimates the gas of a buyTokens call paying
+// amount of an ERC-20 presale's payment token. It fails unless from has
+// approved the amount to the presale.
+func (c *Client) EstimateTokenBuyTokensGas(presale, from common.Address, amount *big.Int) (uint64, error) {
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+
+	args, err := abi.Arguments{{Type: uint256Type}}.Pack(amount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode amount: %w", err)
+	}
+
+	return c.estimateGas(presale, from, nil, append(append([]byte{}, tokenBuyTokensSelector...), args...))
+}
+
+// TokenMetadata reads the symbol and decimals of an ERC-20
+func (c *Client) TokenMetadata(token common.Address) (string, int, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse ERC-20 ABI: %w", err)
+	}
+
+	var symbol string
+	if err := c.call(parsed, token, "symbol", &symbol); err != nil {
+		return "", 0, err
+	}
+
+	var decimals uint8
+	if err := c.call(parsed, token, "decimals", &decimals); err != nil {
+		return "", 0, err
+	}
+
+	return symbol, int(decimals), nil
+}
+
+// call calls a view method without arguments and unpacks its single result
+// into out
+func (c *Client) call(parsed abi.ABI, to common.Address, method string, out interface{}) error {
+	data, err := parsed.Pack(method)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", method, err)
+	}
+
+	result, err := c.Conn.CallContract(context.Background(), ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	if err := parsed.UnpackIntoInterface(out, method, result); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", method, err)
+	}
+	return nil
+}
+
+// estimateGas estimates the gas of a call sending value wei to a contract
+func (c *Client) estimateGas(to, from common.Address, value *big.Int, data []byte) (uint64, error) {
+	gas, err := c.Conn.EstimateGas(context.Background(), ethereum.CallMsg{
+		From:  from,
+		To:    &to,
+		Value: value,
+		Data:  data,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return gas, nil
+}
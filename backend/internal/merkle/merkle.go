@@ -0,0 +1,96 @@
+// Package merkle builds Merkle trees whose proofs verify with OpenZeppelin's
+// MerkleProof library, laid out as @openzeppelin/merkle-tree's
+// StandardMerkleTree lays them out, so both produce the same root for the
+// same values. Pairs are hashed in sorted order, so a proof is a list of
+// sibling hashes without left/right flags.
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tree is a Merkle tree over a list of leaves, stored as a complete binary
+// tree in an array: the root at 0, the children of node i at 2i+1 and 2i+2,
+// and the leaves, sorted, in reverse order at the end
+type Tree struct {
+	nodes     []common.Hash
+	positions []int // node index of each leaf, in the order given to New
+}
+
+// AllocationLeaf is the leaf of an address and the wei it may contribute,
+// keccak256(bytes.concat(keccak256(abi.encode(account, allocation)))). The
+// leaf is hashed twice so it cannot be mistaken for an inner node.
+func AllocationLeaf(account common.Address, allocation *big.Int) common.Hash {
+	encoded := append(common.LeftPadBytes(account.Bytes(), 32), math.U256Bytes(new(big.Int).Set(allocation))...)
+	return crypto.Keccak256Hash(crypto.Keccak256(encoded))
+}
+
+// New builds a tree over leaves, which must not be empty
+func New(leaves []common.Hash) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("merkle tree needs at least one leaf")
+	}
+
+	order := make([]int, len(leaves))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return bytes.Compare(leaves[order[a]][:], leaves[order[b]][:]) < 0
+	})
+
+	nodes := make([]common.Hash, 2*len(leaves)-1)
+	positions := make([]int, len(leaves))
+	for rank, leaf := range order {
+		positions[leaf] = len(nodes) - 1 - rank
+		nodes[positions[leaf]] = leaves[leaf]
+	}
+	for i := len(nodes) - 1 - len(leaves); i >= 0; i-- {
+		nodes[i] = hashPair(nodes[2*i+1], nodes[2*i+2])
+	}
+
+	return &Tree{nodes: nodes, positions: positions}, nil
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() common.Hash {
+	return t.nodes[0]
+}
+
+// Proof returns the sibling hashes proving the leaf given to New at index,
+// bottom up
+func (t *Tree) Proof(index int) []common.Hash {
+	proof := []common.Hash{}
+	for node := t.positions[index]; node > 0; node = (node - 1) / 2 {
+		sibling := node + 1
+		if node%2 == 0 {
+			sibling = node - 1
+		}
+		proof = append(proof, t.nodes[sibling])
+	}
+	return proof
+}
+
+// Verify reports whether proof proves leaf under root
+func Verify(proof []common.Hash, root, leaf common.Hash) bool {
+	computed := leaf
+	for _, sibling := range proof {
+		computed = hashPair(computed, sibling)
+	}
+	return computed == root
+}
+
+// hashPair hashes two nodes in sorted order, as MerkleProof does
+func hashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
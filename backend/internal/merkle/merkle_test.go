@@ -0,0 +1,141 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func allocation(t *testing.T, wei string) *big.Int {
+	t.Helper()
+
+	value, ok := new(big.Int).SetString(wei, 10)
+	if !ok {
+		t.Fatalf("invalid allocation %q", wei)
+	}
+	return value
+}
+
+// The values and root of the @openzeppelin/merkle-tree README:
+// StandardMerkleTree.of(values, ["address", "uint256"])
+func TestStandardMerkleTreeVector(t *testing.T) {
+	first := AllocationLeaf(common.HexToAddress("0x1111111111111111111111111111111111111111"), allocation(t, "5000000000000000000"))
+	second := AllocationLeaf(common.HexToAddress("0x2222222222222222222222222222222222222222"), allocation(t, "2500000000000000000"))
+
+	tree, err := New([]common.Hash{first, second})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	want := common.HexToHash("0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77")
+	if tree.Root() != want {
+		t.Fatalf("root = %s, want %s", tree.Root().Hex(), want.Hex())
+	}
+
+	if proof := tree.Proof(0); len(proof) != 1 || proof[0] != second {
+		t.Fatalf("proof of the first leaf = %v, want [%s]", proof, second.Hex())
+	}
+	if proof := tree.Proof(1); len(proof) != 1 || proof[0] != first {
+		t.Fatalf("proof of the second leaf = %v, want [%s]", proof, first.Hex())
+	}
+}
+
+func TestSingleLeaf(t *testing.T) {
+	leaf := AllocationLeaf(common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(0))
+
+	tree, err := New([]common.Hash{leaf})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if tree.Root() != leaf {
+		t.Fatalf("root = %s, want the leaf %s", tree.Root().Hex(), leaf.Hex())
+	}
+	if proof := tree.Proof(0); len(proof) != 0 {
+		t.Fatalf("proof = %v, want empty", proof)
+	}
+	if !Verify(tree.Proof(0), tree.Root(), leaf) {
+		t.Fatal("empty proof of the only leaf does not verify")
+	}
+}
+
+func TestOddLeafCounts(t *testing.T) {
+	for _, count := range []int{3, 5, 7} {
+		leaves := make([]common.Hash, count)
+		for i := range leaves {
+			leaves[i] = AllocationLeaf(common.BigToAddress(big.NewInt(int64(i+1))), big.NewInt(int64(i)*1e18))
+		}
+
+		tree, err := New(leaves)
+		if err != nil {
+			t.Fatalf("New(%d leaves): %v", count, err)
+		}
+
+		for i, leaf := range leaves {
+			if !Verify(tree.Proof(i), tree.Root(), leaf) {
+				t.Fatalf("%d leaves: proof of leaf %d does not verify", count, i)
+			}
+		}
+	}
+}
+
+// With three leaves sorted as s0 < s1 < s2, StandardMerkleTree stores
+// [root, hash(s0, s1), s2, s1, s0], so s2 is proven by one sibling
+func TestThreeLeafLayout(t *testing.T) {
+	leaves := []common.Hash{
+		common.HexToHash("0x03"),
+		common.HexToHash("0x01"),
+		common.HexToHash("0x02"),
+	}
+
+	tree, err := New(leaves)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	pair := hashPair(leaves[1], leaves[2])
+	if want := hashPair(pair, leaves[0]); tree.Root() != want {
+		t.Fatalf("root = %s, want %s", tree.Root().Hex(), want.Hex())
+	}
+
+	if proof := tree.Proof(0); len(proof) != 1 || proof[0] != pair {
+		t.Fatalf("proof of the largest leaf = %v, want [%s]", proof, pair.Hex())
+	}
+	if proof := tree.Proof(1); len(proof) != 2 || proof[0] != leaves[2] || proof[1] != leaves[0] {
+		t.Fatalf("proof of the smallest leaf = %v", proof)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	leaves := []common.Hash{
+		AllocationLeaf(common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(1)),
+		AllocationLeaf(common.HexToAddress("0x2222222222222222222222222222222222222222"), big.NewInt(2)),
+		AllocationLeaf(common.HexToAddress("0x3333333333333333333333333333333333333333"), big.NewInt(3)),
+	}
+
+	tree, err := New(leaves)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// Another allocation for a whitelisted address
+	forged := AllocationLeaf(common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(100))
+	if Verify(tree.Proof(0), tree.Root(), forged) {
+		t.Fatal("proof verifies a leaf with a different allocation")
+	}
+
+	if Verify(tree.Proof(1), tree.Root(), leaves[0]) {
+		t.Fatal("proof of one leaf verifies another")
+	}
+
+	if Verify(tree.Proof(0), common.HexToHash("0x01"), leaves[0]) {
+		t.Fatal("proof verifies under the wrong root")
+	}
+}
+
+func TestNewRejectsNoLeaves(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Fatal("New(nil) succeeded, want an error")
+	}
+}
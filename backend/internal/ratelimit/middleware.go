@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// KeyFunc identifies the client a request is counted against.
+// An empty key skips rate limiting for the request.
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP keys requests by client IP. It relies on middleware.RealIP having
+// rewritten RemoteAddr when running behind a proxy.
+func KeyByIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return "ip:" + host
+	}
+	return "ip:" + r.RemoteAddr
+}
+
+// Middleware limits requests for a route group. Each group has its own
+// buckets, so the same client is counted separately per group. If the store
+// fails, requests are let through rather than taking the API down.
+func Middleware(store Store, group string, rule Rule, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(r.Context(), "ratelimit:"+group+":"+key, rule)
+			if err != nil {
+				log.Printf("Rate limiter unavailable for %s: %v", group, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter.Milliseconds())))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter.Milliseconds())))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Rate limit exceeded"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ceilSeconds rounds milliseconds up to whole seconds
+func ceilSeconds(milliseconds int64) int {
+	return int((milliseconds + 999) / 1000)
+}
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule configures a token bucket: Limit requests may burst at once, and the
+// bucket refills at Limit tokens per Window
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Result describes the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // time until the next token, when not allowed
+	ResetAfter time.Duration // time until the bucket is full again
+}
+
+// Store takes tokens from buckets identified by key
+type Store interface {
+	Take(ctx context.Context, key string, rule Rule) (*Result, error)
+}
+
+// ParseRule parses a rule such as "60/1m" (60 requests per minute)
+func ParseRule(value string) (Rule, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: expected <limit>/<window>", value)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || limit <= 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: limit must be a positive integer", value)
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || window < time.Millisecond {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: window must be at least 1ms", value)
+	}
+
+	return Rule{Limit: limit, Window: window}, nil
+}
+
+// RuleFromEnv reads a rule from an environment variable, falling back to a default
+func RuleFromEnv(key, defaultValue string) (Rule, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+
+	rule, err := ParseRule(value)
+	if err != nil {
+		return Rule{}, fmt.Errorf("%s: %w", key, err)
+	}
+
+	return rule, nil
+}
+
+// refillPerMillisecond returns how many tokens a bucket gains per millisecond
+func (r Rule) refillPerMillisecond() float64 {
+	return float64(r.Limit) / float64(r.Window.Milliseconds())
+}
+
+// newResult builds a result from the tokens left in a bucket after a take
+func newResult(rule Rule, tokens float64, allowed bool) *Result {
+	rate := rule.refillPerMillisecond()
+
+	result := &Result{
+		Allowed:    allowed,
+		Limit:      rule.Limit,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: time.Duration(math.Ceil((float64(rule.Limit)-tokens)/rate)) * time.Millisecond,
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration(math.Ceil((1-tokens)/rate)) * time.Millisecond
+	}
+
+	return result
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lib/pq"
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+// API key scopes
+const (
+	ScopeTokensRead    = "tokens:read"
+	ScopeTokensWrite   = "tokens:write"
+	ScopePresalesRead  = "presales:read"
+	ScopePresalesWrite = "presales:write"
+)
+
+// apiKeyPrefix marks a bearer credential as an API key rather than a JWT
+const apiKeyPrefix = "lpk_"
+
+const (
+	defaultAPIKeyTTL = 90 * 24 * time.Hour
+	maxAPIKeyTTL     = 365 * 24 * time.Hour
+)
+
+var validScopes = map[string]bool{
+	ScopeTokensRead:    true,
+	ScopeTokensWrite:   true,
+	ScopePresalesRead:  true,
+	ScopePresalesWrite: true,
+}
+
+// APIKeyService handles API keys for server-to-server integrations
+type APIKeyService struct {
+	db *sql.DB
+}
+
+// CreateAPIKeyRequest represents an API key creation request
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expires_at"` // ISO 8601 format, defaults to 90 days
+}
+
+// CreateAPIKeyResponse represents an API key creation response.
+// Key holds the plaintext secret and is never returned again.
+type CreateAPIKeyResponse struct {
+	Key    string          `json:"key"`
+	APIKey *storage.APIKey `json:"api_key"`
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(db *sql.DB) *APIKeyService {
+	return &APIKeyService{
+		db: db,
+	}
+}
+
+// IsAPIKey reports whether a bearer credential looks like an API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, apiKeyPrefix)
+}
+
+// CreateAPIKey issues a new API key for an address
+func (k *APIKeyService) CreateAPIKey(ownerAddress string, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	// Validate input
+	if !common.IsHexAddress(ownerAddress) {
+		return nil, fmt.Errorf("invalid owner address")
+	}
+
+	if req.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	if len(req.Scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+
+	for _, scope := range req.Scopes {
+		if !validScopes[scope] {
+			return nil, fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+
+	// Parse expiry
+	expiresAt := time.Now().Add(defaultAPIKeyTTL)
+	if req.ExpiresAt != "" {
+		var err error
+		expiresAt, err = time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires_at format: %w", err)
+		}
+	}
+
+	if expiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future")
+	}
+
+	if expiresAt.After(time.Now().Add(maxAPIKeyTTL)) {
+		return nil, fmt.Errorf("expires_at must be within one year")
+	}
+
+	// Generate random secret
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	secret := apiKeyPrefix + hex.EncodeToString(bytes)
+
+	apiKey := &storage.APIKey{
+		OwnerAddress: ownerAddress,
+		Name:         req.Name,
+		Prefix:       secret[:len(apiKeyPrefix)+8],
+		KeyHash:      hashAPIKey(secret),
+		Scopes:       req.Scopes,
+		ExpiresAt:    expiresAt,
+	}
+
+	err := k.storeAPIKey(apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store API key: %w", err)
+	}
+
+	return &CreateAPIKeyResponse{
+		Key:    secret,
+		APIKey: apiKey,
+	}, nil
+}
+
+// ListAPIKeys lists API keys owned by an address
+func (k *APIKeyService) ListAPIKeys(ownerAddress string) ([]*storage.APIKey, error) {
+	if !common.IsHexAddress(ownerAddress) {
+		return nil, fmt.Errorf("invalid owner address")
+	}
+
+	query := `
+		SELECT id, owner_address, name, key_prefix, key_hash, scopes, expires_at,
+		       last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE owner_address = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := k.db.Query(query, ownerAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var apiKeys []*storage.APIKey
+	for rows.Next() {
+		apiKey := &storage.APIKey{}
+		err := rows.Scan(
+			&apiKey.ID,
+			&apiKey.OwnerAddress,
+			&apiKey.Name,
+			&apiKey.Prefix,
+			&apiKey.KeyHash,
+			pq.Array(&apiKey.Scopes),
+			&apiKey.ExpiresAt,
+			&apiKey.LastUsedAt,
+			&apiKey.RevokedAt,
+			&apiKey.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey revokes an API key owned by an address
+func (k *APIKeyService) RevokeAPIKey(ownerAddress string, id int) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND owner_address = $2 AND revoked_at IS NULL
+	`
+
+	result, err := k.db.Exec(query, id, ownerAddress)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("API key not found")
+	}
+
+	return nil
+}
+
+// Authenticate resolves a plaintext API key and records its use
+func (k *APIKeyService) Authenticate(secret string) (*storage.APIKey, error) {
+	if !IsAPIKey(secret) {
+		return nil, fmt.Errorf("invalid API key")
+	}
+
+	query := `
+		UPDATE api_keys
+		SET last_used_at = CURRENT_TIMESTAMP
+		WHERE key_hash = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING id, owner_address, name, key_prefix, key_hash, scopes, expires_at,
+		          last_used_at, revoked_at, created_at
+	`
+
+	apiKey := &storage.APIKey{}
+	err := k.db.QueryRow(query, hashAPIKey(secret)).Scan(
+		&apiKey.ID,
+		&apiKey.OwnerAddress,
+		&apiKey.Name,
+		&apiKey.Prefix,
+		&apiKey.KeyHash,
+		pq.Array(&apiKey.Scopes),
+		&apiKey.ExpiresAt,
+		&apiKey.LastUsedAt,
+		&apiKey.RevokedAt,
+		&apiKey.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid API key")
+		}
+		return nil, fmt.Errorf("failed to authenticate API key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// HasScope reports whether an API key grants a scope
+func HasScope(apiKey *storage.APIKey, scope string) bool {
+	for _, s := range apiKey.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// storeAPIKey stores an API key in the database
+func (k *APIKeyService) storeAPIKey(apiKey *storage.APIKey) error {
+	query := `
+		INSERT INTO api_keys (owner_address, name, key_prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := k.db.QueryRow(
+		query,
+		apiKey.OwnerAddress,
+		apiKey.Name,
+		apiKey.Prefix,
+		apiKey.KeyHash,
+		pq.Array(apiKey.Scopes),
+		apiKey.ExpiresAt,
+	).Scan(&apiKey.ID, &apiKey.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert API key: %w", err)
+	}
+
+	return nil
+}
+
+// hashAPIKey returns the hex-encoded SHA-256 hash of an API key secret
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
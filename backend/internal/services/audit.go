@@ -0,0 +1,187 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+// Audit event outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditService records and queries the append-only audit log
+type AuditService struct {
+	db *sql.DB
+}
+
+// AuditFilter holds the filters of an audit log query. Empty fields match everything.
+type AuditFilter struct {
+	Actor     string
+	Action    string
+	Outcome   string
+	RequestID string
+	From      string // ISO 8601 format
+	To        string // ISO 8601 format
+	BeforeID  string // return events older than this ID, for paging
+	Limit     string
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(db *sql.DB) *AuditService {
+	return &AuditService{
+		db: db,
+	}
+}
+
+// Record appends an event to the audit log
+func (a *AuditService) Record(event *storage.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (action, actor_address, api_key_id, request_id, ip, method, path,
+		                          payload_hash, outcome, status_code)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+
+	err := a.db.QueryRow(
+		query,
+		event.Action,
+		event.ActorAddress,
+		event.APIKeyID,
+		event.RequestID,
+		event.IP,
+		event.Method,
+		event.Path,
+		event.PayloadHash,
+		event.Outcome,
+		event.StatusCode,
+	).Scan(&event.ID, &event.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+// ListEvents lists audit events matching a filter, newest first
+func (a *AuditService) ListEvents(filter *AuditFilter) ([]*storage.AuditEvent, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Actor != "" {
+		addCondition("actor_address = $%d", filter.Actor)
+	}
+
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+
+	if filter.Outcome != "" {
+		if filter.Outcome != AuditOutcomeSuccess && filter.Outcome != AuditOutcomeFailure {
+			return nil, fmt.Errorf("invalid outcome")
+		}
+		addCondition("outcome = $%d", filter.Outcome)
+	}
+
+	if filter.RequestID != "" {
+		addCondition("request_id = $%d", filter.RequestID)
+	}
+
+	if filter.From != "" {
+		from, err := time.Parse(time.RFC3339, filter.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from format: %w", err)
+		}
+		addCondition("created_at >= $%d", from)
+	}
+
+	if filter.To != "" {
+		to, err := time.Parse(time.RFC3339, filter.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to format: %w", err)
+		}
+		addCondition("created_at < $%d", to)
+	}
+
+	if filter.BeforeID != "" {
+		beforeID, err := strconv.ParseInt(filter.BeforeID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid before_id")
+		}
+		addCondition("id < $%d", beforeID)
+	}
+
+	limit := defaultAuditLimit
+	if filter.Limit != "" {
+		var err error
+		limit, err = strconv.Atoi(filter.Limit)
+		if err != nil || limit <= 0 || limit > maxAuditLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxAuditLimit)
+		}
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT id, action, actor_address, api_key_id, request_id, ip, method, path,
+		       payload_hash, outcome, status_code, created_at
+		FROM audit_events
+		%s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, where, len(args))
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*storage.AuditEvent
+	for rows.Next() {
+		event := &storage.AuditEvent{}
+		err := rows.Scan(
+			&event.ID,
+			&event.Action,
+			&event.ActorAddress,
+			&event.APIKeyID,
+			&event.RequestID,
+			&event.IP,
+			&event.Method,
+			&event.Path,
+			&event.PayloadHash,
+			&event.Outcome,
+			&event.StatusCode,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -2,22 +2,38 @@ package services
 
 import (
 	"crypto/rand"
+	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/wrestler094/launchpad/internal/storage"
 )
 
 // AuthService handles authentication
 type AuthService struct {
-	jwtSecret []byte
-	nonces    map[string]NonceInfo // In production, use Redis
+	db          *sql.DB
+	jwtSecret   []byte
+	noncesMu    sync.Mutex
+	nonces      map[string]NonceInfo // In production, use Redis
+	lastPrune   time.Time
+	userService *UserService
 }
 
+// ErrMalformedSignature is returned when a signature is not 65 bytes of
+// 0x-prefixed hex
+var ErrMalformedSignature = errors.New("signature must be 0x followed by 65 bytes of hex")
+
+// maxPendingNonces bounds the nonce map between prunes of expired entries
+const maxPendingNonces = 100000
+
 // NonceInfo stores nonce information
 type NonceInfo struct {
 	Nonce     string
@@ -33,8 +49,17 @@ type LoginRequest struct {
 
 // LoginResponse represents a login response
 type LoginResponse struct {
-	Token   string `json:"token"`
-	Address string `json:"address"`
+	Token     string `json:"token"`
+	Address   string `json:"address"`
+	Role      string `json:"role"`
+	AccountID int    `json:"account_id"`
+}
+
+// LinkWalletRequest represents a request to link a wallet to an account
+type LinkWalletRequest struct {
+	Address   string `json:"address"`
+	Signature string `json:"signature"`
+	Nonce     string `json:"nonce"`
 }
 
 // NonceResponse represents a nonce response
@@ -44,18 +69,23 @@ type NonceResponse struct {
 
 // Claims represents JWT claims
 type Claims struct {
-	Address string `json:"address"`
+	Address   string `json:"address"`
+	Role      string `json:"role"`
+	AccountID int    `json:"account_id"`
 	jwt.RegisteredClaims
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService() *AuthService {
+func NewAuthService(db *sql.DB, userService *UserService) *AuthService {
 	// In production, load this from environment
 	jwtSecret := []byte("your-secret-key-change-this-in-production")
 	
 	return &AuthService{
-		jwtSecret: jwtSecret,
-		nonces:    make(map[string]NonceInfo),
+		db:          db,
+		jwtSecret:   jwtSecret,
+		nonces:      make(map[string]NonceInfo),
+		lastPrune:   time.Now(),
+		userService: userService,
 	}
 }
 
@@ -74,6 +104,14 @@ func (a *AuthService) GenerateNonce(address string) (*NonceResponse, error) {
 	
 	nonce := hex.EncodeToString(bytes)
 	
+	a.noncesMu.Lock()
+	defer a.noncesMu.Unlock()
+
+	a.pruneNonces()
+	if _, exists := a.nonces[address]; !exists && len(a.nonces) >= maxPendingNonces {
+		return nil, fmt.Errorf("too many pending logins, try again later")
+	}
+
 	// Store nonce with expiration (5 minutes)
 	a.nonces[address] = NonceInfo{
 		Nonce:     nonce,
@@ -85,51 +123,123 @@ func (a *AuthService) GenerateNonce(address string) (*NonceResponse, error) {
 	}, nil
 }
 
-// Login authenticates a user with MetaMask signature
-func (a *AuthService) Login(req *LoginRequest) (*LoginResponse, error) {
+// Login authenticates a user with MetaMask signature and starts a session
+func (a *AuthService) Login(req *LoginRequest, info SessionInfo) (*LoginResponse, error) {
+	// Validate address
+	if !common.IsHexAddress(req.Address) {
+		return nil, fmt.Errorf("invalid ethereum address")
+	}
+
+	// Verify signature
+	message := fmt.Sprintf("Sign this message to authenticate with Launchpad.\n\nNonce: %s", req.Nonce)
+
+	if err := a.verifySignedNonce(req.Address, req.Nonce, message, req.Signature); err != nil {
+		return nil, err
+	}
+
+	// Record user and load role
+	user, err := a.userService.UpsertUser(req.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record user: %w", err)
+	}
+
+	// Start session
+	session, err := a.createSession(user.Address, info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// Generate JWT token
+	token, err := a.generateJWT(user, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &LoginResponse{
+		Token:     token,
+		Address:   user.Address,
+		Role:      user.Role,
+		AccountID: user.AccountID,
+	}, nil
+}
+
+// LinkWallet links another wallet to the account of an authenticated address.
+// The wallet proves ownership by signing a nonce issued by GenerateNonce.
+func (a *AuthService) LinkWallet(accountAddress string, req *LinkWalletRequest) (*storage.User, error) {
 	// Validate address
 	if !common.IsHexAddress(req.Address) {
 		return nil, fmt.Errorf("invalid ethereum address")
 	}
 
+	account, err := a.userService.UpsertUser(accountAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account: %w", err)
+	}
+
+	// Verify signature
+	message := fmt.Sprintf("Sign this message to link this wallet to Launchpad account %d.\n\nNonce: %s", account.AccountID, req.Nonce)
+
+	if err := a.verifySignedNonce(req.Address, req.Nonce, message, req.Signature); err != nil {
+		return nil, err
+	}
+
+	return a.userService.LinkWallet(account.AccountID, req.Address)
+}
+
+// verifySignedNonce checks that an address signed a message containing its
+// outstanding nonce, and consumes the nonce
+func (a *AuthService) verifySignedNonce(address, nonce, message, signature string) error {
+	sig, err := decodeSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	a.noncesMu.Lock()
+	defer a.noncesMu.Unlock()
+
 	// Check nonce
-	nonceInfo, exists := a.nonces[req.Address]
+	nonceInfo, exists := a.nonces[address]
 	if !exists {
-		return nil, fmt.Errorf("nonce not found")
+		return fmt.Errorf("nonce not found")
 	}
 
 	if time.Now().After(nonceInfo.ExpiresAt) {
-		delete(a.nonces, req.Address)
-		return nil, fmt.Errorf("nonce expired")
+		delete(a.nonces, address)
+		return fmt.Errorf("nonce expired")
 	}
 
-	if nonceInfo.Nonce != req.Nonce {
-		return nil, fmt.Errorf("invalid nonce")
+	if nonceInfo.Nonce != nonce {
+		return fmt.Errorf("invalid nonce")
 	}
 
-	// Verify signature
-	message := fmt.Sprintf("Sign this message to authenticate with Launchpad.\n\nNonce: %s", req.Nonce)
-	
-	if !a.verifySignature(req.Address, message, req.Signature) {
-		return nil, fmt.Errorf("invalid signature")
+	if !a.verifySignature(address, message, sig) {
+		return fmt.Errorf("invalid signature")
 	}
 
 	// Clean up nonce
-	delete(a.nonces, req.Address)
+	delete(a.nonces, address)
 
-	// Generate JWT token
-	token, err := a.generateJWT(req.Address)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+	return nil
+}
+
+// pruneNonces drops expired nonces at most once a minute.
+// The caller must hold noncesMu.
+func (a *AuthService) pruneNonces() {
+	now := time.Now()
+	if now.Sub(a.lastPrune) < time.Minute {
+		return
 	}
 
-	return &LoginResponse{
-		Token:   token,
-		Address: req.Address,
-	}, nil
+	for address, nonceInfo := range a.nonces {
+		if now.After(nonceInfo.ExpiresAt) {
+			delete(a.nonces, address)
+		}
+	}
+
+	a.lastPrune = now
 }
 
-// VerifyToken verifies a JWT token and returns the address
+// VerifyToken verifies a JWT token and its session and returns the claims
 func (a *AuthService) VerifyToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -143,18 +253,24 @@ func (a *AuthService) VerifyToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		if err := a.validateSession(claims); err != nil {
+			return nil, err
+		}
 		return claims, nil
 	}
 
 	return nil, fmt.Errorf("invalid token")
 }
 
-// generateJWT generates a JWT token for an address
-func (a *AuthService) generateJWT(address string) (string, error) {
+// generateJWT generates a JWT token for a user's session
+func (a *AuthService) generateJWT(user *storage.User, session *storage.Session) (string, error) {
 	claims := &Claims{
-		Address: address,
+		Address:   user.Address,
+		Role:      user.Role,
+		AccountID: user.AccountID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ID:        session.ID,
+			ExpiresAt: jwt.NewNumericDate(session.ExpiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -163,18 +279,27 @@ func (a *AuthService) generateJWT(address string) (string, error) {
 	return token.SignedString(a.jwtSecret)
 }
 
-// verifySignature verifies an Ethereum signature
-func (a *AuthService) verifySignature(address, message, signature string) bool {
-	// Hash the message
-	hash := accounts.TextHash([]byte(message))
+// decodeSignature decodes a 0x-prefixed 65-byte signature
+func decodeSignature(signature string) ([]byte, error) {
+	if !strings.HasPrefix(signature, "0x") {
+		return nil, ErrMalformedSignature
+	}
 
-	// Decode signature
-	sig, err := hex.DecodeString(signature[2:]) // Remove 0x prefix
-	if err != nil {
-		return false
+	sig, err := hex.DecodeString(signature[2:])
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return nil, ErrMalformedSignature
 	}
 
-	// Adjust recovery ID for Ethereum
+	return sig, nil
+}
+
+// verifySignature verifies an Ethereum signature decoded by decodeSignature
+func (a *AuthService) verifySignature(address, message string, sig []byte) bool {
+	// Hash the message
+	hash := accounts.TextHash([]byte(message))
+
+	// Adjust recovery ID for Ethereum, leaving the caller's bytes alone
+	sig = append([]byte(nil), sig...)
 	if sig[64] == 27 || sig[64] == 28 {
 		sig[64] -= 27
 	}
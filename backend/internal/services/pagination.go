@@ -0,0 +1,83 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// PageRequest holds the paging parameters of a list request. Empty fields
+// use defaults: newest first, 20 rows.
+type PageRequest struct {
+	Sort   string
+	Order  string // asc or desc
+	Cursor string // next_cursor of the previous page
+	Limit  string
+}
+
+// pageQuery validates the paging parameters. The sort key itself is
+// validated by the repository.
+func (p *PageRequest) pageQuery() (storage.PageQuery, error) {
+	query := storage.PageQuery{
+		Sort:  p.Sort,
+		Order: p.Order,
+		Limit: defaultPageLimit,
+	}
+
+	if query.Sort == "" {
+		query.Sort = storage.SortCreatedAt
+	}
+
+	if query.Order == "" {
+		query.Order = storage.OrderDesc
+	}
+
+	if p.Limit != "" {
+		limit, err := strconv.Atoi(p.Limit)
+		if err != nil || limit <= 0 || limit > maxPageLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		query.Limit = limit
+	}
+
+	if p.Cursor != "" {
+		cursor, err := storage.DecodeCursor(p.Cursor)
+		if err != nil {
+			return query, err
+		}
+		query.After = cursor
+	}
+
+	return query, nil
+}
+
+// encodeCursor returns the opaque form of a next-page cursor, or an empty
+// string on the last page
+func encodeCursor(cursor *storage.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return cursor.Encode()
+}
+
+// parseTimeParam parses an optional ISO 8601 query parameter
+func parseTimeParam(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s format: %w", name, err)
+	}
+
+	t = t.UTC()
+	return &t, nil
+}
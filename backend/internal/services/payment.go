@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wrestler094/launchpad/internal/storage"
+	"github.com/wrestler094/launchpad/internal/units"
+)
+
+const (
+	maxPaymentSymbolLength = 20
+	maxPaymentDecimals     = 36
+)
+
+// paymentCurrency resolves the currency a new presale is paid in: ETH, or
+// the ERC-20 at req.PaymentToken. A token's symbol and decimals are read
+// from the chain when the client is configured, and taken from the request
+// otherwise.
+func (p *PresaleService) paymentCurrency(req *CreatePresaleRequest) (string, units.Unit, error) {
+	if req.PaymentToken == "" {
+		return "", units.Ether, nil
+	}
+
+	if !common.IsHexAddress(req.PaymentToken) {
+		return "", units.Unit{}, fmt.Errorf("invalid payment token address")
+	}
+	token := common.HexToAddress(req.PaymentToken)
+
+	if strings.EqualFold(token.Hex(), req.TokenAddress) {
+		return "", units.Unit{}, fmt.Errorf("payment token must differ from the token sold")
+	}
+
+	unit := units.Unit{Symbol: strings.TrimSpace(req.PaymentSymbol)}
+	if req.PaymentDecimals != nil {
+		unit.Decimals = *req.PaymentDecimals
+	}
+
+	if p.client != nil {
+		symbol, decimals, err := p.client.TokenMetadata(token)
+		if err != nil {
+			return "", units.Unit{}, fmt.Errorf("failed to read payment token: %w", err)
+		}
+		if req.PaymentDecimals != nil && *req.PaymentDecimals != decimals {
+			return "", units.Unit{}, fmt.Errorf("payment token has %d decimals, not %d", decimals, *req.PaymentDecimals)
+		}
+		unit = units.Unit{Symbol: symbol, Decimals: decimals}
+	} else if req.PaymentDecimals == nil {
+		return "", units.Unit{}, fmt.Errorf("payment_decimals is required while the chain cannot be read")
+	}
+
+	if unit.Symbol == "" || len(unit.Symbol) > maxPaymentSymbolLength || strings.ContainsAny(unit.Symbol, " \t\n") {
+		return "", units.Unit{}, fmt.Errorf("payment symbol must be 1 to %d characters without spaces", maxPaymentSymbolLength)
+	}
+
+	if unit.Decimals < 0 || unit.Decimals > maxPaymentDecimals {
+		return "", units.Unit{}, fmt.Errorf("payment decimals must be between 0 and %d", maxPaymentDecimals)
+	}
+
+	return token.Hex(), unit, nil
+}
+
+// parsePayment parses an amount paid to a presale into base units of its
+// payment currency: ETH with the units of ParseETH, or a token with its
+// symbol, as in "100 USDC". Amounts without a unit are base units (wei for
+// ETH), and a fractional amount without a unit is rejected.
+func parsePayment(amount, paymentToken string, unit units.Unit) (*big.Int, error) {
+	if paymentToken == "" {
+		return units.ParseETH(amount, units.Wei)
+	}
+	return units.ParseToken(amount, unit)
+}
+
+// presalePayment parses an amount paid to an existing presale
+func presalePayment(presale *storage.Presale, amount string) (*big.Int, error) {
+	return parsePayment(amount, presale.PaymentToken, presale.PaymentUnit())
+}
+
+// contractRate converts a rate in whole tokens per whole unit of the payment
+// currency into the contract's token base units per payment base unit
+func contractRate(rate *big.Int, paymentDecimals int) (*big.Int, error) {
+	shift := storage.TokenDecimals - paymentDecimals
+	if shift >= 0 {
+		return new(big.Int).Mul(rate, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil)), nil
+	}
+
+	scaled, rem := new(big.Int).QuoRem(rate, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil), new(big.Int))
+	if scaled.Sign() == 0 || rem.Sign() != 0 {
+		return nil, fmt.Errorf("rate must be a multiple of %s for a payment token with %d decimals", new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil), paymentDecimals)
+	}
+	return scaled, nil
+}
@@ -1,29 +1,48 @@
 package services
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/wrestler094/launchpad/internal/contracts"
 	"github.com/wrestler094/launchpad/internal/storage"
+	"github.com/wrestler094/launchpad/internal/units"
 )
 
 // PresaleService handles presale-related operations
 type PresaleService struct {
-	client *contracts.Client
-	db     *sql.DB
+	client         *contracts.Client
+	presales       storage.PresaleRepository
+	tokens         storage.TokenRepository
+	participations storage.ParticipationRepository
+	whitelists     storage.WhitelistRepository
+	vesting        storage.VestingRepository
 }
 
 // CreatePresaleRequest represents a presale creation request
 type CreatePresaleRequest struct {
-	TokenAddress string `json:"token_address"`
-	Rate         string `json:"rate"`
-	SoftCap      string `json:"soft_cap"`
-	HardCap      string `json:"hard_cap"`
-	Deadline     string `json:"deadline"` // ISO 8601 format
+	TokenAddress    string `json:"token_address"`
+	Rate            string `json:"rate"`             // whole tokens per ETH, or per whole payment token
+	SoftCap         string `json:"soft_cap"`         // "10 ETH", "500 gwei", "1000 USDC"; base units without a unit
+	HardCap         string `json:"hard_cap"`         // "10 ETH", "500 gwei", "1000 USDC"; base units without a unit
+	MinContribution string `json:"min_contribution"` // per wallet, as soft_cap; empty for no limit
+	MaxContribution string `json:"max_contribution"` // per wallet, as soft_cap; empty for no limit
+	StartTime       string `json:"start_time"`       // ISO 8601 format; empty or past opens when funded
+	Deadline        string `json:"deadline"`         // ISO 8601 format
+	Draft           bool   `json:"draft"`            // start as a draft instead of unfunded
+	Whitelisted     bool   `json:"whitelisted"`      // only addresses on an uploaded whitelist may contribute
+
+	Vesting *VestingRequest       `json:"vesting"` // hold bought tokens and release them on a schedule; nil to transfer them at purchase
+	Rounds  []PresaleRoundRequest `json:"rounds"`  // sell in rounds with their own rates; rate and start_time default to the first round's
+
+	PaymentToken    string `json:"payment_token"`    // ERC-20 contributions are paid in; empty for ETH
+	PaymentSymbol   string `json:"payment_symbol"`   // of payment_token, read from the chain when it is reachable
+	PaymentDecimals *int   `json:"payment_decimals"` // of payment_token, read from the chain when it is reachable
 }
 
 // CreatePresaleResponse represents a presale creation response
@@ -36,21 +55,135 @@ type CreatePresaleResponse struct {
 
 // ParticipateRequest represents a presale participation request
 type ParticipateRequest struct {
-	AmountETH string `json:"amount_eth"`
-	TxHash    string `json:"tx_hash"`
+	Amount       string `json:"amount"`     // "1.5 ETH", "250 gwei", "100 USDC"; base units without a unit, as in quotes
+	AmountETH    string `json:"amount_eth"` // deprecated name of amount, read when amount is empty
+	TxHash       string `json:"tx_hash"`
+	AllowPartial bool   `json:"allow_partial"` // trim the amount to the remaining capacity instead of rejecting it
 }
 
 // ParticipateResponse represents a presale participation response
 type ParticipateResponse struct {
-	AmountTokens   string `json:"amount_tokens"`
-	Participation  *storage.PresaleParticipation `json:"participation"`
+	AmountTokens      string                        `json:"amount_tokens"`
+	Participation     *storage.PresaleParticipation `json:"participation"`
+	Requested         storage.BigInt                `json:"requested"`
+	Trimmed           bool                          `json:"trimmed"` // amount_paid of the participation is less than requested
+	RemainingCapacity storage.BigInt                `json:"remaining_capacity"`
+
+	RemainingCapacityFormatted string `json:"remaining_capacity_formatted"`
+}
+
+// ErrPresaleNotFound is returned when a presale does not exist
+var ErrPresaleNotFound = errors.New("presale not found")
+
+// Errors returned when a contribution does not fit under the hard cap
+var (
+	ErrHardCapReached  = errors.New("presale hard cap reached")
+	ErrExceedsCapacity = errors.New("contribution exceeds remaining capacity")
+)
+
+// Errors returned when a wallet's total contribution is outside the
+// presale's per-wallet limits
+var (
+	ErrBelowMinContribution    = errors.New("contribution below the minimum per wallet")
+	ErrMaxContributionExceeded = errors.New("contribution exceeds the maximum per wallet")
+)
+
+// ListPresalesRequest represents the query of a presale list. Sort is
+// created_at, start_time, deadline, hard_cap or progress; status is a comma-separated
+// list of presale statuses.
+type ListPresalesRequest struct {
+	PageRequest
+	Status       string
+	TokenAddress string
+	CreatedFrom  string // ISO 8601 format
+	CreatedTo    string // ISO 8601 format
+	DeadlineFrom string // ISO 8601 format
+	DeadlineTo   string // ISO 8601 format
+}
+
+// Public presale categories
+const (
+	PresaleCategoryLive              = "live"
+	PresaleCategoryUpcoming          = "upcoming"
+	PresaleCategoryEndingSoon        = "ending_soon"
+	PresaleCategoryNearlyFilled      = "nearly_filled"
+	PresaleCategoryRecentlyFinalized = "recently_finalized"
+)
+
+const (
+	endingSoonWindow        = 48 * time.Hour
+	nearlyFilledBps         = 8000
+	recentlyFinalizedWindow = 30 * 24 * time.Hour
+)
+
+// ListPublicPresalesRequest represents the query of the public presale feed.
+// Sort is created_at, start_time, deadline, hard_cap or progress; each category has its
+// own default order.
+type ListPublicPresalesRequest struct {
+	PageRequest
+	Category string
+}
+
+// PortfolioPosition is a participant's stake in one presale
+type PortfolioPosition struct {
+	*storage.PortfolioPosition
+	Status         string `json:"status"`
+	RefundEligible bool   `json:"refund_eligible"` // finalized below the soft cap, or cancelled
+}
+
+// PortfolioTotals sums a participant's positions. Contributed and
+// Refundable sum ETH presales; presales paid in a token are summed per token.
+type PortfolioTotals struct {
+	Presales            int                   `json:"presales"`
+	Contributed         storage.BigInt        `json:"contributed"`
+	TokensReceived      storage.BigInt        `json:"tokens_received"`
+	Refundable          storage.BigInt        `json:"refundable"`
+	RefundablePositions int                   `json:"refundable_positions"`
+	PaymentTokens       []*PaymentTokenTotals `json:"payment_tokens"`
+}
+
+// PaymentTokenTotals sums a participant's positions in presales paid in one
+// token, in its base units
+type PaymentTokenTotals struct {
+	PaymentToken    string         `json:"payment_token"`
+	PaymentSymbol   string         `json:"payment_symbol"`
+	PaymentDecimals int            `json:"payment_decimals"`
+	Contributed     storage.BigInt `json:"contributed"`
+	Refundable      storage.BigInt `json:"refundable"`
+
+	ContributedFormatted string `json:"contributed_formatted"`
+	RefundableFormatted  string `json:"refundable_formatted"`
+}
+
+// Portfolio represents everything a participant joined
+type Portfolio struct {
+	Positions []*PortfolioPosition `json:"positions"`
+	Totals    PortfolioTotals      `json:"totals"`
+}
+
+const (
+	defaultContributorsLimit = 100
+	maxContributorsLimit     = 1000
+)
+
+// ListContributorsRequest represents the query of a presale's contributors.
+// Sort is contributed (default), contributions, first_contributed_at or
+// last_contributed_at; Limit selects the top N.
+type ListContributorsRequest struct {
+	Sort  string
+	Order string
+	Limit string
 }
 
 // NewPresaleService creates a new presale service
-func NewPresaleService(client *contracts.Client, db *sql.DB) *PresaleService {
+func NewPresaleService(client *contracts.Client, presales storage.PresaleRepository, tokens storage.TokenRepository, participations storage.ParticipationRepository, whitelists storage.WhitelistRepository, vesting storage.VestingRepository) *PresaleService {
 	return &PresaleService{
-		client: client,
-		db:     db,
+		client:         client,
+		presales:       presales,
+		tokens:         tokens,
+		participations: participations,
+		whitelists:     whitelists,
+		vesting:        vesting,
 	}
 }
 
@@ -65,20 +198,84 @@ func (p *PresaleService) CreatePresale(creatorAddress string, req *CreatePresale
 		return nil, fmt.Errorf("invalid token address")
 	}
 
+	// ERC20PaymentPresale does not check whitelists, contribution limits,
+	// vesting or rounds
+	if req.PaymentToken != "" && (req.Whitelisted || req.MinContribution != "" || req.MaxContribution != "" || req.Vesting != nil || len(req.Rounds) > 0) {
+		return nil, fmt.Errorf("presales paid in a token cannot be whitelisted, limit contributions, vest or have rounds")
+	}
+
+	paymentToken, paymentUnit, err := p.paymentCurrency(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Multi-round presales sell at the rate of the open round
+	if req.Rate == "" && len(req.Rounds) > 0 {
+		req.Rate = req.Rounds[0].Rate
+	}
+
 	// Parse numbers
-	rate, ok := new(big.Int).SetString(req.Rate, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid rate")
+	rate, err := units.ParseUnits(req.Rate, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate: %w", err)
+	}
+
+	softCap, err := parsePayment(req.SoftCap, paymentToken, paymentUnit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid soft cap: %w", err)
 	}
 
-	softCap, ok := new(big.Int).SetString(req.SoftCap, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid soft cap")
+	hardCap, err := parsePayment(req.HardCap, paymentToken, paymentUnit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hard cap: %w", err)
 	}
 
-	hardCap, ok := new(big.Int).SetString(req.HardCap, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid hard cap")
+	if rate.Sign() <= 0 || softCap.Sign() <= 0 || hardCap.Sign() <= 0 {
+		return nil, fmt.Errorf("rate, soft cap and hard cap must be positive")
+	}
+
+	// The contract sells token base units per base unit of the payment currency
+	if rate, err = contractRate(rate, paymentUnit.Decimals); err != nil {
+		return nil, err
+	}
+
+	if softCap.Cmp(hardCap) >= 0 {
+		return nil, fmt.Errorf("soft cap must be less than hard cap")
+	}
+
+	minContribution, err := parseOptionalETH(req.MinContribution)
+	if err != nil {
+		return nil, fmt.Errorf("invalid min contribution: %w", err)
+	}
+
+	maxContribution, err := parseOptionalETH(req.MaxContribution)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max contribution: %w", err)
+	}
+
+	if err := validateContributionLimits(minContribution, maxContribution, hardCap); err != nil {
+		return nil, err
+	}
+
+	// WhitelistPresale limits wallets by their allocations instead
+	if req.Whitelisted && (minContribution.Sign() > 0 || maxContribution.Sign() > 0) {
+		return nil, fmt.Errorf("whitelisted presales limit contributions with whitelist allocations, not min and max contribution")
+	}
+
+	// VestingPresale does not check whitelists or contribution limits
+	if req.Vesting != nil {
+		if err := validateVesting(req.Vesting); err != nil {
+			return nil, err
+		}
+
+		if req.Whitelisted || minContribution.Sign() > 0 || maxContribution.Sign() > 0 {
+			return nil, fmt.Errorf("vesting presales cannot be whitelisted or limit contributions")
+		}
+	}
+
+	// TieredPresale does not check whitelists, contribution limits or vesting
+	if len(req.Rounds) > 0 && (req.Whitelisted || minContribution.Sign() > 0 || maxContribution.Sign() > 0 || req.Vesting != nil) {
+		return nil, fmt.Errorf("multi-round presales cannot be whitelisted, limit contributions or vest")
 	}
 
 	// Parse deadline
@@ -91,8 +288,33 @@ func (p *PresaleService) CreatePresale(creatorAddress string, req *CreatePresale
 		return nil, fmt.Errorf("deadline must be in the future")
 	}
 
+	rounds, err := parseRounds(req.Rounds, deadline, hardCap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse start time; the contract opens at it, or at once if it has passed.
+	// Multi-round presales open with their first round by default.
+	startTime := time.Now()
+	if req.StartTime != "" {
+		startTime, err = time.Parse(time.RFC3339, req.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time format: %w", err)
+		}
+	} else if len(rounds) > 0 {
+		startTime = rounds[0].StartTime
+	}
+
+	if !startTime.Before(deadline) {
+		return nil, fmt.Errorf("start time must be before the deadline")
+	}
+
+	if len(rounds) > 0 && rounds[0].StartTime.Before(startTime) {
+		return nil, fmt.Errorf("rounds must not start before the start time")
+	}
+
 	// Verify token exists
-	tokenExists, err := p.verifyTokenExists(req.TokenAddress)
+	tokenExists, err := p.tokens.Exists(req.TokenAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify token: %w", err)
 	}
@@ -109,19 +331,39 @@ func (p *PresaleService) CreatePresale(creatorAddress string, req *CreatePresale
 
 	// Store presale in database
 	presale := &storage.Presale{
-		Address:        presaleAddress.Hex(),
-		TokenAddress:   req.TokenAddress,
-		CreatorAddress: creatorAddress,
-		Rate:           rate.String(),
-		SoftCap:        softCap.String(),
-		HardCap:        hardCap.String(),
-		Deadline:       deadline,
-		TxHash:         txHash,
-		Active:         true,
-		Finalized:      false,
-	}
-
-	err = p.storePresale(presale)
+		Address:         presaleAddress.Hex(),
+		TokenAddress:    req.TokenAddress,
+		CreatorAddress:  creatorAddress,
+		Rate:            storage.NewBigInt(rate),
+		SoftCap:         storage.NewBigInt(softCap),
+		HardCap:         storage.NewBigInt(hardCap),
+		MinContribution: storage.NewBigInt(minContribution),
+		MaxContribution: storage.NewBigInt(maxContribution),
+		StartTime:       startTime.UTC(),
+		Deadline:        deadline.UTC(),
+		TxHash:          txHash,
+		Status:          storage.PresaleStatusUnfunded,
+		Whitelisted:     req.Whitelisted,
+		PaymentToken:    paymentToken,
+		PaymentSymbol:   paymentUnit.Symbol,
+		PaymentDecimals: paymentUnit.Decimals,
+		Rounds:          rounds,
+	}
+
+	if req.Vesting != nil {
+		presale.Vesting = true
+		presale.VestingTGEBps = req.Vesting.TGEBps
+		presale.VestingCliff = req.Vesting.CliffSeconds
+		presale.VestingDuration = req.Vesting.DurationSeconds
+	}
+
+	// The factory deploys the presale without tokens, so it waits for the
+	// creator to fund it unless it is kept as a draft
+	if req.Draft {
+		presale.Status = storage.PresaleStatusDraft
+	}
+
+	err = p.presales.Create(presale)
 	if err != nil {
 		return nil, fmt.Errorf("failed to store presale: %w", err)
 	}
@@ -139,32 +381,10 @@ func (p *PresaleService) CreatePresale(creatorAddress string, req *CreatePresale
 
 // GetPresale gets a presale by ID
 func (p *PresaleService) GetPresale(id int) (*storage.Presale, error) {
-	query := `
-		SELECT id, address, token_address, creator_address, rate, soft_cap, hard_cap, 
-		       deadline, tx_hash, active, finalized, created_at
-		FROM presales 
-		WHERE id = $1
-	`
-
-	presale := &storage.Presale{}
-	err := p.db.QueryRow(query, id).Scan(
-		&presale.ID,
-		&presale.Address,
-		&presale.TokenAddress,
-		&presale.CreatorAddress,
-		&presale.Rate,
-		&presale.SoftCap,
-		&presale.HardCap,
-		&presale.Deadline,
-		&presale.TxHash,
-		&presale.Active,
-		&presale.Finalized,
-		&presale.CreatedAt,
-	)
-
+	presale, err := p.presales.GetByID(id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("presale not found")
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrPresaleNotFound
 		}
 		return nil, fmt.Errorf("failed to get presale: %w", err)
 	}
@@ -172,50 +392,236 @@ func (p *PresaleService) GetPresale(id int) (*storage.Presale, error) {
 	return presale, nil
 }
 
-// ListPresales lists presales created by a user
-func (p *PresaleService) ListPresales(creatorAddress string) ([]*storage.Presale, error) {
-	if !common.IsHexAddress(creatorAddress) {
-		return nil, fmt.Errorf("invalid creator address")
+// GetPublicPresale gets a presale for public pages. Drafts are visible to
+// their creator only, so they are not found here.
+func (p *PresaleService) GetPublicPresale(id int) (*storage.Presale, error) {
+	presale, err := p.GetPresale(id)
+	if err != nil {
+		return nil, err
 	}
 
-	query := `
-		SELECT id, address, token_address, creator_address, rate, soft_cap, hard_cap, 
-		       deadline, tx_hash, active, finalized, created_at
-		FROM presales 
-		WHERE creator_address = $1 
-		ORDER BY created_at DESC
-	`
+	if presale.Status == storage.PresaleStatusDraft {
+		return nil, ErrPresaleNotFound
+	}
+
+	return presale, nil
+}
+
+// ListPresales lists a page of presales created by any of a user's wallets
+// and returns the cursor of the next page, empty on the last page
+func (p *PresaleService) ListPresales(creatorAddresses []string, req *ListPresalesRequest) ([]*storage.Presale, string, error) {
+	if len(creatorAddresses) == 0 {
+		return nil, "", fmt.Errorf("no creator addresses")
+	}
+
+	for _, creatorAddress := range creatorAddresses {
+		if !common.IsHexAddress(creatorAddress) {
+			return nil, "", fmt.Errorf("invalid creator address")
+		}
+	}
 
-	rows, err := p.db.Query(query, creatorAddress)
+	page, err := req.pageQuery()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list presales: %w", err)
-	}
-	defer rows.Close()
-
-	var presales []*storage.Presale
-	for rows.Next() {
-		presale := &storage.Presale{}
-		err := rows.Scan(
-			&presale.ID,
-			&presale.Address,
-			&presale.TokenAddress,
-			&presale.CreatorAddress,
-			&presale.Rate,
-			&presale.SoftCap,
-			&presale.HardCap,
-			&presale.Deadline,
-			&presale.TxHash,
-			&presale.Active,
-			&presale.Finalized,
-			&presale.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan presale: %w", err)
+		return nil, "", err
+	}
+
+	query := &storage.PresaleQuery{
+		PageQuery:        page,
+		CreatorAddresses: creatorAddresses,
+	}
+
+	if req.Status != "" {
+		query.Statuses = strings.Split(req.Status, ",")
+	}
+
+	if req.TokenAddress != "" {
+		if !common.IsHexAddress(req.TokenAddress) {
+			return nil, "", fmt.Errorf("invalid token address")
+		}
+		query.TokenAddress = req.TokenAddress
+	}
+
+	if query.CreatedFrom, err = parseTimeParam("created_from", req.CreatedFrom); err != nil {
+		return nil, "", err
+	}
+	if query.CreatedTo, err = parseTimeParam("created_to", req.CreatedTo); err != nil {
+		return nil, "", err
+	}
+	if query.DeadlineFrom, err = parseTimeParam("deadline_from", req.DeadlineFrom); err != nil {
+		return nil, "", err
+	}
+	if query.DeadlineTo, err = parseTimeParam("deadline_to", req.DeadlineTo); err != nil {
+		return nil, "", err
+	}
+
+	presales, next, err := p.presales.List(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return presales, encodeCursor(next), nil
+}
+
+// ListPublicPresales lists a page of presales of every creator in a
+// discovery category, with their tokens, and returns the cursor of the next page
+func (p *PresaleService) ListPublicPresales(req *ListPublicPresalesRequest) ([]*storage.Presale, string, error) {
+	now := time.Now().UTC()
+	query := &storage.PresaleQuery{IncludeToken: true}
+
+	defaultSort, defaultOrder := storage.SortProgress, storage.OrderDesc
+	switch req.Category {
+	case PresaleCategoryLive, "":
+		query.Statuses = []string{storage.PresaleStatusLive}
+	case PresaleCategoryUpcoming:
+		query.Statuses = []string{storage.PresaleStatusScheduled}
+		defaultSort, defaultOrder = storage.SortStartTime, storage.OrderAsc
+	case PresaleCategoryEndingSoon:
+		query.Statuses = []string{storage.PresaleStatusLive}
+		endsBefore := now.Add(endingSoonWindow)
+		query.DeadlineTo = &endsBefore
+		defaultSort, defaultOrder = storage.SortDeadline, storage.OrderAsc
+	case PresaleCategoryNearlyFilled:
+		query.Statuses = []string{storage.PresaleStatusLive}
+		query.MinProgressBps = nearlyFilledBps
+	case PresaleCategoryRecentlyFinalized:
+		query.Statuses = []string{storage.PresaleStatusFinalizedSuccess, storage.PresaleStatusFinalizedFailed}
+		finalizedAfter := now.Add(-recentlyFinalizedWindow)
+		query.StatusFrom = &finalizedAfter
+		defaultSort = storage.SortDeadline
+	default:
+		return nil, "", fmt.Errorf("invalid category")
+	}
+
+	pageReq := req.PageRequest
+	if pageReq.Sort == "" {
+		pageReq.Sort = defaultSort
+		if pageReq.Order == "" {
+			pageReq.Order = defaultOrder
 		}
-		presales = append(presales, presale)
 	}
 
-	return presales, nil
+	page, err := pageReq.pageQuery()
+	if err != nil {
+		return nil, "", err
+	}
+	query.PageQuery = page
+
+	presales, next, err := p.presales.List(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return presales, encodeCursor(next), nil
+}
+
+// GetPortfolio sums the participations of a user's wallets per presale,
+// with each presale's status and refund eligibility
+func (p *PresaleService) GetPortfolio(participantAddresses []string) (*Portfolio, error) {
+	for _, participantAddress := range participantAddresses {
+		if !common.IsHexAddress(participantAddress) {
+			return nil, fmt.Errorf("invalid participant address")
+		}
+	}
+
+	positions, err := p.participations.Portfolio(participantAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := &Portfolio{Positions: make([]*PortfolioPosition, 0, len(positions))}
+	portfolio.Totals.PaymentTokens = []*PaymentTokenTotals{}
+	contributed, tokensReceived, refundable := new(big.Int), new(big.Int), new(big.Int)
+	byPaymentToken := make(map[string]*PaymentTokenTotals)
+
+	for _, position := range positions {
+		presale := position.Presale
+		entry := &PortfolioPosition{
+			PortfolioPosition: position,
+			Status:            presale.Status,
+			RefundEligible:    isRefundable(presale.Status),
+		}
+		portfolio.Positions = append(portfolio.Positions, entry)
+
+		tokensReceived.Add(tokensReceived, position.TokensReceived.Big())
+		if entry.RefundEligible {
+			portfolio.Totals.RefundablePositions++
+		}
+
+		if presale.PaymentToken == "" {
+			contributed.Add(contributed, position.Contributed.Big())
+			if entry.RefundEligible {
+				refundable.Add(refundable, position.Contributed.Big())
+			}
+			continue
+		}
+
+		totals, ok := byPaymentToken[presale.PaymentToken]
+		if !ok {
+			totals = &PaymentTokenTotals{
+				PaymentToken:    presale.PaymentToken,
+				PaymentSymbol:   presale.PaymentSymbol,
+				PaymentDecimals: presale.PaymentDecimals,
+			}
+			byPaymentToken[presale.PaymentToken] = totals
+			portfolio.Totals.PaymentTokens = append(portfolio.Totals.PaymentTokens, totals)
+		}
+
+		totals.Contributed = storage.NewBigInt(new(big.Int).Add(totals.Contributed.Big(), position.Contributed.Big()))
+		if entry.RefundEligible {
+			totals.Refundable = storage.NewBigInt(new(big.Int).Add(totals.Refundable.Big(), position.Contributed.Big()))
+		}
+	}
+
+	portfolio.Totals.Presales = len(positions)
+	portfolio.Totals.Contributed = storage.NewBigInt(contributed)
+	portfolio.Totals.TokensReceived = storage.NewBigInt(tokensReceived)
+	portfolio.Totals.Refundable = storage.NewBigInt(refundable)
+
+	for _, totals := range portfolio.Totals.PaymentTokens {
+		unit := units.Unit{Symbol: totals.PaymentSymbol, Decimals: totals.PaymentDecimals}
+		totals.ContributedFormatted = units.FormatAmount(totals.Contributed.Big(), unit)
+		totals.RefundableFormatted = units.FormatAmount(totals.Refundable.Big(), unit)
+	}
+
+	return portfolio, nil
+}
+
+// ListContributors lists the contributors of a presale with per-address
+// totals, rank and share of the raise. Callers check the requester is the creator.
+func (p *PresaleService) ListContributors(presaleID int, req *ListContributorsRequest) ([]*storage.Contributor, error) {
+	query := &storage.ContributorQuery{
+		PresaleID: presaleID,
+		Sort:      req.Sort,
+		Order:     req.Order,
+		Limit:     defaultContributorsLimit,
+	}
+
+	if query.Sort == "" {
+		query.Sort = storage.SortContributed
+	}
+
+	if query.Order == "" {
+		query.Order = storage.OrderDesc
+	}
+
+	if req.Limit != "" {
+		limit, err := strconv.Atoi(req.Limit)
+		if err != nil || limit <= 0 || limit > maxContributorsLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxContributorsLimit)
+		}
+		query.Limit = limit
+	}
+
+	contributors, err := p.participations.Contributors(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if contributors == nil {
+		contributors = []*storage.Contributor{}
+	}
+
+	return contributors, nil
 }
 
 // ParticipateInPresale records a user's participation in a presale
@@ -225,112 +631,192 @@ func (p *PresaleService) ParticipateInPresale(presaleID int, participantAddress
 		return nil, fmt.Errorf("invalid participant address")
 	}
 
-	amountETH, ok := new(big.Int).SetString(req.AmountETH, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid ETH amount")
-	}
-
-	// Get presale info
+	// Neither the payment currency nor, once the presale is live, the
+	// whitelist can change, so both are read before taking the lock
 	presale, err := p.GetPresale(presaleID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get presale: %w", err)
+		return nil, err
 	}
 
-	if !presale.Active {
-		return nil, fmt.Errorf("presale is not active")
+	amount := req.Amount
+	if amount == "" {
+		amount = req.AmountETH
 	}
 
-	if time.Now().After(presale.Deadline) {
-		return nil, fmt.Errorf("presale has ended")
+	amountPaid, err := presalePayment(presale, amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
 	}
 
-	// Calculate tokens
-	rate, _ := new(big.Int).SetString(presale.Rate, 10)
-	amountTokens := new(big.Int).Mul(amountETH, rate)
+	if amountPaid.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount")
+	}
 
-	// Store participation
-	participation := &storage.PresaleParticipation{
-		PresaleID:       presaleID,
-		ParticipantAddr: participantAddress,
-		AmountETH:       amountETH.String(),
-		AmountTokens:    amountTokens.String(),
-		TxHash:          req.TxHash,
+	allocation, err := p.whitelistAllocation(presale, participantAddress)
+	if err != nil {
+		return nil, err
 	}
 
-	err = p.storeParticipation(participation)
+	// Check the presale and reserve capacity under the presale's lock, so
+	// concurrent contributions are accepted one at a time
+	participation, remaining, err := p.participations.Reserve(presaleID, participantAddress, func(presale *storage.Presale, remaining, contributed *big.Int) (*storage.PresaleParticipation, error) {
+		switch presale.Status {
+		case storage.PresaleStatusLive:
+		case storage.PresaleStatusFilled:
+			return nil, ErrHardCapReached
+		case storage.PresaleStatusScheduled:
+			return nil, fmt.Errorf("presale opens at %s", presale.StartTime.Format(time.RFC3339))
+		default:
+			return nil, fmt.Errorf("presale is %s, not live", presale.Status)
+		}
+
+		accepted, err := allocate(presale, amountPaid, remaining, req.AllowPartial)
+		if err != nil {
+			return nil, err
+		}
+
+		// Multi-round presales sell at the open round's rate up to its cap
+		rate := presale.Rate.Big()
+		var roundIndex *int
+		if len(presale.Rounds) > 0 {
+			now := time.Now()
+			round := activeRound(presale.Rounds, now)
+			if round == nil {
+				return nil, noActiveRoundError(presale.Rounds, now)
+			}
+
+			if left := roundLeft(round); left != nil {
+				if accepted, err = allocateLimit(presale, accepted, left, req.AllowPartial, ErrRoundCapReached); err != nil {
+					return nil, err
+				}
+			}
+
+			rate = round.Rate.Big()
+			roundIndex = &round.Index
+		}
+
+		if allocation != nil {
+			left := new(big.Int).Sub(allocation, contributed)
+			if accepted, err = allocateLimit(presale, accepted, left, req.AllowPartial, ErrAllocationExceeded); err != nil {
+				return nil, err
+			}
+		}
+
+		if maxContribution := presale.MaxContribution.Big(); maxContribution.Sign() > 0 {
+			left := new(big.Int).Sub(maxContribution, contributed)
+			if accepted, err = allocateLimit(presale, accepted, left, req.AllowPartial, ErrMaxContributionExceeded); err != nil {
+				return nil, err
+			}
+		}
+
+		// A wallet's total must reach the minimum, so later top-ups may be smaller
+		if total := new(big.Int).Add(contributed, accepted); total.Cmp(presale.MinContribution.Big()) < 0 {
+			return nil, fmt.Errorf("%w of %s", ErrBelowMinContribution, presale.FormatPaid(presale.MinContribution.Big()))
+		}
+
+		// Calculate tokens
+		amountTokens := new(big.Int).Mul(accepted, rate)
+
+		return &storage.PresaleParticipation{
+			AmountPaid:   storage.NewBigInt(accepted),
+			AmountTokens: storage.NewBigInt(amountTokens),
+			TxHash:       req.TxHash,
+			RoundIndex:   roundIndex,
+		}, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to store participation: %w", err)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrPresaleNotFound
+		}
+		return nil, err
 	}
 
 	return &ParticipateResponse{
-		AmountTokens:  amountTokens.String(),
-		Participation: participation,
+		AmountTokens:      participation.AmountTokens.String(),
+		Participation:     participation,
+		Requested:         storage.NewBigInt(amountPaid),
+		Trimmed:           participation.AmountPaid.Cmp(storage.NewBigInt(amountPaid)) < 0,
+		RemainingCapacity: remaining,
+
+		RemainingCapacityFormatted: presale.FormatPaid(remaining.Big()),
 	}, nil
 }
 
-// verifyTokenExists checks if a token exists in the database
-func (p *PresaleService) verifyTokenExists(tokenAddress string) (bool, error) {
-	query := `SELECT 1 FROM tokens WHERE address = $1`
-	var exists int
-	err := p.db.QueryRow(query, tokenAddress).Scan(&exists)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return false, nil
+// allocate returns the part of a contribution that fits into the remaining
+// hard-cap capacity of a presale. Without allowPartial the contribution must
+// fit whole.
+func allocate(presale *storage.Presale, amount, remaining *big.Int, allowPartial bool) (*big.Int, error) {
+	if remaining.Sign() <= 0 {
+		return nil, ErrHardCapReached
+	}
+
+	if amount.Cmp(remaining) <= 0 {
+		return amount, nil
+	}
+
+	if !allowPartial {
+		return nil, fmt.Errorf("%w: %s left", ErrExceedsCapacity, presale.FormatPaid(remaining))
+	}
+
+	return new(big.Int).Set(remaining), nil
+}
+
+// allocateLimit is allocate for what is left of a per-wallet or per-round
+// limit, failing with limitErr when the amount does not fit
+func allocateLimit(presale *storage.Presale, amount, left *big.Int, allowPartial bool, limitErr error) (*big.Int, error) {
+	if amount.Cmp(left) <= 0 {
+		return amount, nil
+	}
+
+	if !allowPartial || left.Sign() <= 0 {
+		if left.Sign() < 0 {
+			left = new(big.Int)
 		}
-		return false, err
+		return nil, fmt.Errorf("%w: %s left", limitErr, presale.FormatPaid(left))
 	}
-	return true, nil
+
+	return new(big.Int).Set(left), nil
 }
 
-// storePresale stores a presale in the database
-func (p *PresaleService) storePresale(presale *storage.Presale) error {
-	query := `
-		INSERT INTO presales (address, token_address, creator_address, rate, soft_cap, hard_cap, deadline, tx_hash, active, finalized)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, created_at
-	`
-
-	err := p.db.QueryRow(
-		query,
-		presale.Address,
-		presale.TokenAddress,
-		presale.CreatorAddress,
-		presale.Rate,
-		presale.SoftCap,
-		presale.HardCap,
-		presale.Deadline,
-		presale.TxHash,
-		presale.Active,
-		presale.Finalized,
-	).Scan(&presale.ID, &presale.CreatedAt)
+// validateContributionLimits checks min <= max <= hard cap for the
+// per-wallet limits, where 0 is no limit
+func validateContributionLimits(minContribution, maxContribution, hardCap *big.Int) error {
+	if minContribution.Cmp(hardCap) > 0 {
+		return fmt.Errorf("min contribution must not exceed hard cap")
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to insert presale: %w", err)
+	if maxContribution.Sign() == 0 {
+		return nil
+	}
+
+	if minContribution.Cmp(maxContribution) > 0 {
+		return fmt.Errorf("min contribution must not exceed max contribution")
+	}
+
+	if maxContribution.Cmp(hardCap) > 0 {
+		return fmt.Errorf("max contribution must not exceed hard cap")
 	}
 
 	return nil
 }
 
-// storeParticipation stores a participation in the database
-func (p *PresaleService) storeParticipation(participation *storage.PresaleParticipation) error {
-	query := `
-		INSERT INTO presale_participations (presale_id, participant_address, amount_eth, amount_tokens, tx_hash)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
-	`
-
-	err := p.db.QueryRow(
-		query,
-		participation.PresaleID,
-		participation.ParticipantAddr,
-		participation.AmountETH,
-		participation.AmountTokens,
-		participation.TxHash,
-	).Scan(&participation.ID, &participation.CreatedAt)
+// parseOptionalETH parses an ETH amount, wei without a unit, that may be
+// empty for 0
+func parseOptionalETH(amount string) (*big.Int, error) {
+	if strings.TrimSpace(amount) == "" {
+		return new(big.Int), nil
+	}
+	return units.ParseETH(amount, units.Wei)
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to insert participation: %w", err)
+// ListParticipations lists participations made by any of a user's wallets
+func (p *PresaleService) ListParticipations(participantAddresses []string) ([]*storage.PresaleParticipation, error) {
+	for _, participantAddress := range participantAddresses {
+		if !common.IsHexAddress(participantAddress) {
+			return nil, fmt.Errorf("invalid participant address")
+		}
 	}
 
-	return nil
-}
\ No newline at end of file
+	return p.participations.ListByParticipants(participantAddresses)
+}
+
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+// ErrInvalidTransition is returned when a presale cannot move to a status
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// presaleTransitions lists the statuses a presale may be moved to from each
+// status. Live, filled and ended are reached by the start time, the raise and
+// the deadline rather than by a transition; cancelled and the finalized
+// statuses are terminal.
+var presaleTransitions = map[string][]string{
+	storage.PresaleStatusDraft:     {storage.PresaleStatusUnfunded, storage.PresaleStatusCancelled},
+	storage.PresaleStatusUnfunded:  {storage.PresaleStatusScheduled, storage.PresaleStatusCancelled},
+	storage.PresaleStatusScheduled: {storage.PresaleStatusCancelled},
+	storage.PresaleStatusLive:      {storage.PresaleStatusCancelled},
+	storage.PresaleStatusFilled:    {storage.PresaleStatusFinalizedSuccess},
+	storage.PresaleStatusEnded:     {storage.PresaleStatusFinalizedSuccess, storage.PresaleStatusFinalizedFailed},
+}
+
+// TransitionPresaleRequest represents a presale status change. Status is
+// unfunded (publish a draft), scheduled (the presale holds its tokens and
+// opens at its start time), cancelled, finalized_success or finalized_failed.
+type TransitionPresaleRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// PresaleStatusResponse represents a presale's status and its history
+type PresaleStatusResponse struct {
+	PresaleID   int                            `json:"presale_id"`
+	Status      string                         `json:"status"`
+	Transitions []string                       `json:"transitions"` // statuses the presale can move to now
+	History     []*storage.PresaleStatusChange `json:"history"`
+}
+
+const maxTransitionReasonLength = 500
+
+// TransitionPresale moves a presale to a new status if its current status
+// allows it. A whitelisted presale is scheduled only once its whitelist is
+// uploaded. Finalizing must match the outcome: success needs the soft cap
+// raised, failure a raise below it. Callers check the actor is the creator.
+func (p *PresaleService) TransitionPresale(presaleID int, actorAddress string, req *TransitionPresaleRequest) (*storage.Presale, error) {
+	if !common.IsHexAddress(actorAddress) {
+		return nil, fmt.Errorf("invalid actor address")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > maxTransitionReasonLength {
+		return nil, fmt.Errorf("reason must be at most %d characters", maxTransitionReasonLength)
+	}
+
+	change := &storage.PresaleStatusChange{
+		PresaleID:    presaleID,
+		ToStatus:     req.Status,
+		ActorAddress: actorAddress,
+		Reason:       reason,
+	}
+
+	presale, err := p.presales.Transition(change, func(presale *storage.Presale) error {
+		if !canTransition(presale.Status, req.Status) {
+			return fmt.Errorf("%w: %s to %q", ErrInvalidTransition, presale.Status, req.Status)
+		}
+
+		if req.Status == storage.PresaleStatusScheduled && presale.Whitelisted && presale.MerkleRoot == "" {
+			return fmt.Errorf("%w: whitelist not uploaded", ErrInvalidTransition)
+		}
+
+		softCapRaised := presale.Raised.Cmp(presale.SoftCap) >= 0
+		if req.Status == storage.PresaleStatusFinalizedSuccess && !softCapRaised {
+			return fmt.Errorf("%w: soft cap not raised", ErrInvalidTransition)
+		}
+		if req.Status == storage.PresaleStatusFinalizedFailed && softCapRaised {
+			return fmt.Errorf("%w: soft cap raised", ErrInvalidTransition)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrPresaleNotFound
+		}
+		return nil, err
+	}
+
+	return presale, nil
+}
+
+// GetPresaleStatus returns a presale's status, the statuses it can move to
+// and its status history
+func (p *PresaleService) GetPresaleStatus(presaleID int) (*PresaleStatusResponse, error) {
+	presale, err := p.GetPresale(presaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := p.presales.StatusHistory(presaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if history == nil {
+		history = []*storage.PresaleStatusChange{}
+	}
+
+	transitions := presaleTransitions[presale.Status]
+	if transitions == nil {
+		transitions = []string{}
+	}
+
+	return &PresaleStatusResponse{
+		PresaleID:   presale.ID,
+		Status:      presale.Status,
+		Transitions: transitions,
+		History:     history,
+	}, nil
+}
+
+// canTransition reports whether a presale may move from one status to another
+func canTransition(from, to string) bool {
+	for _, allowed := range presaleTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isRefundable reports whether contributions to a presale in a status can
+// be claimed back
+func isRefundable(status string) bool {
+	return status == storage.PresaleStatusFinalizedFailed || status == storage.PresaleStatusCancelled
+}
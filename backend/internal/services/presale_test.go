@@ -0,0 +1,366 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+const (
+	testToken   = "0x1000000000000000000000000000000000000001"
+	testCreator = "0x2000000000000000000000000000000000000002"
+	testBuyer   = "0x3000000000000000000000000000000000000003"
+	testBuyer2  = "0x4000000000000000000000000000000000000004"
+)
+
+// newTestRepositories returns in-memory repositories that hold testToken
+func newTestRepositories(t *testing.T) *storage.Repositories {
+	t.Helper()
+
+	repos := storage.NewMemoryRepositories()
+	if err := repos.Tokens.Create(&storage.Token{Address: testToken, Name: "Test", Symbol: "TST", CreatorAddress: testCreator}); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	return repos
+}
+
+// newTestService returns a PresaleService over in-memory repositories that
+// hold testToken, without a chain client
+func newTestService(t *testing.T) *PresaleService {
+	t.Helper()
+
+	repos := newTestRepositories(t)
+	return NewPresaleService(nil, repos.Presales, repos.Tokens, repos.Participations, repos.Whitelists, repos.Vesting)
+}
+
+// createLivePresale creates a presale from req, filling in the token and a
+// deadline a day away, and schedules it so it opens at its start time
+func createLivePresale(t *testing.T, p *PresaleService, req *CreatePresaleRequest) *storage.Presale {
+	t.Helper()
+
+	req.TokenAddress = testToken
+	if req.Deadline == "" {
+		req.Deadline = time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	}
+
+	created, err := p.CreatePresale(testCreator, req)
+	if err != nil {
+		t.Fatalf("CreatePresale: %v", err)
+	}
+
+	presale, err := p.TransitionPresale(created.Presale.ID, testCreator, &TransitionPresaleRequest{Status: storage.PresaleStatusScheduled})
+	if err != nil {
+		t.Fatalf("schedule presale: %v", err)
+	}
+	return presale
+}
+
+func participate(p *PresaleService, presaleID int, buyer, amount string, allowPartial bool) (*ParticipateResponse, error) {
+	return p.ParticipateInPresale(presaleID, buyer, &ParticipateRequest{Amount: amount, TxHash: "0x01", AllowPartial: allowPartial})
+}
+
+// The contract requires softCap < hardCap, so equal caps are rejected too
+func TestCreatePresaleCaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		softCap string
+		hardCap string
+		wantErr bool
+	}{
+		{name: "soft below hard", softCap: "1 ETH", hardCap: "2 ETH"},
+		{name: "equal caps", softCap: "2 ETH", hardCap: "2 ETH", wantErr: true},
+		{name: "soft above hard", softCap: "3 ETH", hardCap: "2 ETH", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestService(t)
+			_, err := p.CreatePresale(testCreator, &CreatePresaleRequest{
+				TokenAddress: testToken,
+				Rate:         "1000",
+				SoftCap:      tt.softCap,
+				HardCap:      tt.hardCap,
+				Deadline:     time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			})
+			if tt.wantErr && err == nil {
+				t.Fatal("CreatePresale succeeded, want a cap error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("CreatePresale: %v", err)
+			}
+		})
+	}
+}
+
+func TestParticipateHardCap(t *testing.T) {
+	p := newTestService(t)
+	presale := createLivePresale(t, p, &CreatePresaleRequest{Rate: "1000", SoftCap: "1 ETH", HardCap: "2 ETH"})
+
+	if presale.Status != storage.PresaleStatusLive {
+		t.Fatalf("status = %s, want live", presale.Status)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer, "1.5 ETH", false); err != nil {
+		t.Fatalf("first contribution: %v", err)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer2, "1 ETH", false); !errors.Is(err, ErrExceedsCapacity) {
+		t.Fatalf("contribution past the hard cap: err = %v, want ErrExceedsCapacity", err)
+	}
+
+	resp, err := participate(p, presale.ID, testBuyer2, "1 ETH", true)
+	if err != nil {
+		t.Fatalf("partial contribution: %v", err)
+	}
+	if !resp.Trimmed || resp.Participation.AmountPaid.String() != "500000000000000000" {
+		t.Fatalf("partial contribution paid %s (trimmed %v), want 0.5 ETH trimmed", resp.Participation.AmountPaid, resp.Trimmed)
+	}
+	if resp.Participation.AmountTokens.String() != "500000000000000000000" {
+		t.Fatalf("tokens = %s, want 500 tokens", resp.Participation.AmountTokens)
+	}
+	if resp.RemainingCapacity.Sign() != 0 {
+		t.Fatalf("remaining capacity = %s, want 0", resp.RemainingCapacity)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer, "1 wei", true); !errors.Is(err, ErrHardCapReached) {
+		t.Fatalf("contribution to a filled presale: err = %v, want ErrHardCapReached", err)
+	}
+
+	filled, err := p.GetPresale(presale.ID)
+	if err != nil {
+		t.Fatalf("GetPresale: %v", err)
+	}
+	if filled.Status != storage.PresaleStatusFilled {
+		t.Fatalf("status = %s, want filled", filled.Status)
+	}
+}
+
+func TestParticipateContributionLimits(t *testing.T) {
+	p := newTestService(t)
+	presale := createLivePresale(t, p, &CreatePresaleRequest{
+		Rate:            "1000",
+		SoftCap:         "1 ETH",
+		HardCap:         "10 ETH",
+		MinContribution: "0.5 ETH",
+		MaxContribution: "2 ETH",
+	})
+
+	if _, err := participate(p, presale.ID, testBuyer, "0.1 ETH", false); !errors.Is(err, ErrBelowMinContribution) {
+		t.Fatalf("contribution below the minimum: err = %v, want ErrBelowMinContribution", err)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer, "1.5 ETH", false); err != nil {
+		t.Fatalf("contribution within the limits: %v", err)
+	}
+
+	// The wallet's total is past the minimum, so a smaller top-up is fine
+	if _, err := participate(p, presale.ID, testBuyer, "0.1 ETH", false); err != nil {
+		t.Fatalf("top-up below the minimum: %v", err)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer, "1 ETH", false); !errors.Is(err, ErrMaxContributionExceeded) {
+		t.Fatalf("contribution past the maximum: err = %v, want ErrMaxContributionExceeded", err)
+	}
+
+	resp, err := participate(p, presale.ID, testBuyer, "1 ETH", true)
+	if err != nil {
+		t.Fatalf("partial contribution up to the maximum: %v", err)
+	}
+	if resp.Participation.AmountPaid.String() != "400000000000000000" {
+		t.Fatalf("partial contribution paid %s, want 0.4 ETH", resp.Participation.AmountPaid)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer, "1 wei", true); !errors.Is(err, ErrMaxContributionExceeded) {
+		t.Fatalf("contribution at the maximum: err = %v, want ErrMaxContributionExceeded", err)
+	}
+}
+
+func TestParticipateRoundCaps(t *testing.T) {
+	p := newTestService(t)
+	now := time.Now()
+	presale := createLivePresale(t, p, &CreatePresaleRequest{
+		SoftCap: "1 ETH",
+		HardCap: "10 ETH",
+		Rounds: []PresaleRoundRequest{
+			{Name: "seed", Rate: "2000", Cap: "1 ETH", StartTime: now.Add(-time.Minute).Format(time.RFC3339), EndTime: now.Add(time.Hour).Format(time.RFC3339)},
+			{Name: "public", Rate: "1000", StartTime: now.Add(2 * time.Hour).Format(time.RFC3339), EndTime: now.Add(3 * time.Hour).Format(time.RFC3339)},
+		},
+	})
+
+	resp, err := participate(p, presale.ID, testBuyer, "0.6 ETH", false)
+	if err != nil {
+		t.Fatalf("contribution to the seed round: %v", err)
+	}
+	if resp.Participation.RoundIndex == nil || *resp.Participation.RoundIndex != 0 {
+		t.Fatalf("round index = %v, want 0", resp.Participation.RoundIndex)
+	}
+	if resp.Participation.AmountTokens.String() != "1200000000000000000000" {
+		t.Fatalf("tokens = %s, want 1200 at the seed rate", resp.Participation.AmountTokens)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer2, "0.5 ETH", false); !errors.Is(err, ErrRoundCapReached) {
+		t.Fatalf("contribution past the round cap: err = %v, want ErrRoundCapReached", err)
+	}
+
+	resp, err = participate(p, presale.ID, testBuyer2, "0.5 ETH", true)
+	if err != nil {
+		t.Fatalf("partial contribution up to the round cap: %v", err)
+	}
+	if resp.Participation.AmountPaid.String() != "400000000000000000" {
+		t.Fatalf("partial contribution paid %s, want 0.4 ETH", resp.Participation.AmountPaid)
+	}
+
+	if _, err := participate(p, presale.ID, testBuyer2, "1 wei", true); !errors.Is(err, ErrRoundCapReached) {
+		t.Fatalf("contribution to a filled round: err = %v, want ErrRoundCapReached", err)
+	}
+}
+
+func TestParticipateBareAmountIsBaseUnits(t *testing.T) {
+	p := newTestService(t)
+	presale := createLivePresale(t, p, &CreatePresaleRequest{Rate: "1000", SoftCap: "1 ETH", HardCap: "2 ETH"})
+
+	if _, err := participate(p, presale.ID, testBuyer, "1.5", false); err == nil {
+		t.Fatal("bare fractional amount accepted, want an error asking for a unit")
+	}
+	if _, err := p.QuoteParticipation(presale.ID, "1.5", ""); err == nil {
+		t.Fatal("bare fractional quote accepted, want an error asking for a unit")
+	}
+
+	quote, err := p.QuoteParticipation(presale.ID, "1000", "")
+	if err != nil {
+		t.Fatalf("QuoteParticipation: %v", err)
+	}
+	resp, err := participate(p, presale.ID, testBuyer, "1000", false)
+	if err != nil {
+		t.Fatalf("ParticipateInPresale: %v", err)
+	}
+	if quote.Amount.Cmp(resp.Participation.AmountPaid) != 0 || quote.Tokens.Cmp(resp.Participation.AmountTokens) != 0 {
+		t.Fatalf("quote of %s for %s tokens, participation of %s for %s", quote.Amount, quote.Tokens, resp.Participation.AmountPaid, resp.Participation.AmountTokens)
+	}
+}
+
+// assertDraftHidden checks that the public reads leave out the draft presale id
+func assertDraftHidden(t *testing.T, p *PresaleService, search *SearchService, id int) {
+	t.Helper()
+
+	if _, err := p.GetPublicPresale(id); !errors.Is(err, ErrPresaleNotFound) {
+		t.Fatalf("public read of a draft: err = %v, want ErrPresaleNotFound", err)
+	}
+
+	if _, err := p.QuoteParticipation(id, "1 ETH", ""); !errors.Is(err, ErrPresaleNotFound) {
+		t.Fatalf("quote of a draft: err = %v, want ErrPresaleNotFound", err)
+	}
+
+	for _, term := range []string{"Test", testToken, testCreator} {
+		results, err := search.Search(term, "")
+		if err != nil {
+			t.Fatalf("Search(%q): %v", term, err)
+		}
+		for _, result := range results.Presales {
+			if result.Presale.ID == id {
+				t.Fatalf("Search(%q) found the draft", term)
+			}
+		}
+	}
+}
+
+func TestTransitionPresale(t *testing.T) {
+	repos := newTestRepositories(t)
+	p := NewPresaleService(nil, repos.Presales, repos.Tokens, repos.Participations, repos.Whitelists, repos.Vesting)
+	search := NewSearchService(repos.Tokens, repos.Presales)
+
+	created, err := p.CreatePresale(testCreator, &CreatePresaleRequest{
+		TokenAddress: testToken,
+		Rate:         "1000",
+		SoftCap:      "1 ETH",
+		HardCap:      "2 ETH",
+		Deadline:     time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		Draft:        true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePresale: %v", err)
+	}
+	id := created.Presale.ID
+
+	// Only the creator sees a draft, through the authenticated read
+	assertDraftHidden(t, p, search, id)
+
+	steps := []struct {
+		to    string
+		legal bool
+	}{
+		{to: storage.PresaleStatusScheduled, legal: false}, // a draft is published first
+		{to: storage.PresaleStatusFinalizedSuccess, legal: false},
+		{to: storage.PresaleStatusLive, legal: false}, // computed, never a transition
+		{to: "paused", legal: false},
+		{to: storage.PresaleStatusUnfunded, legal: true},
+		{to: storage.PresaleStatusDraft, legal: false},
+		{to: storage.PresaleStatusScheduled, legal: true},
+		{to: storage.PresaleStatusFinalizedFailed, legal: false}, // live until the deadline
+		{to: storage.PresaleStatusCancelled, legal: true},
+		{to: storage.PresaleStatusScheduled, legal: false}, // cancelled is terminal
+	}
+
+	for _, step := range steps {
+		_, err := p.TransitionPresale(id, testCreator, &TransitionPresaleRequest{Status: step.to})
+		if step.legal && err != nil {
+			t.Fatalf("transition to %s: %v", step.to, err)
+		}
+		if !step.legal && !errors.Is(err, ErrInvalidTransition) {
+			t.Fatalf("transition to %s: err = %v, want ErrInvalidTransition", step.to, err)
+		}
+	}
+
+	status, err := p.GetPresaleStatus(id)
+	if err != nil {
+		t.Fatalf("GetPresaleStatus: %v", err)
+	}
+	if status.Status != storage.PresaleStatusCancelled || len(status.History) != 4 {
+		t.Fatalf("status %s with %d history entries, want cancelled with 4", status.Status, len(status.History))
+	}
+
+	if _, err := p.TransitionPresale(id+1, testCreator, &TransitionPresaleRequest{Status: storage.PresaleStatusCancelled}); !errors.Is(err, ErrPresaleNotFound) {
+		t.Fatalf("transition of a missing presale: err = %v, want ErrPresaleNotFound", err)
+	}
+}
+
+func TestDraftsAreNotPublic(t *testing.T) {
+	repos := newTestRepositories(t)
+	p := NewPresaleService(nil, repos.Presales, repos.Tokens, repos.Participations, repos.Whitelists, repos.Vesting)
+	search := NewSearchService(repos.Tokens, repos.Presales)
+
+	created, err := p.CreatePresale(testCreator, &CreatePresaleRequest{
+		TokenAddress: testToken,
+		Rate:         "1000",
+		SoftCap:      "1 ETH",
+		HardCap:      "2 ETH",
+		Deadline:     time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		Draft:        true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePresale: %v", err)
+	}
+	id := created.Presale.ID
+
+	assertDraftHidden(t, p, search, id)
+
+	// Published, the presale is public
+	if _, err := p.TransitionPresale(id, testCreator, &TransitionPresaleRequest{Status: storage.PresaleStatusUnfunded}); err != nil {
+		t.Fatalf("publish draft: %v", err)
+	}
+	if _, err := p.GetPublicPresale(id); err != nil {
+		t.Fatalf("public read of a published presale: %v", err)
+	}
+	if _, err := p.QuoteParticipation(id, "1 ETH", ""); err != nil {
+		t.Fatalf("quote of a published presale: %v", err)
+	}
+	results, err := search.Search(testToken, "")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results.Presales) != 1 || results.Presales[0].Presale.ID != id {
+		t.Fatalf("Search found %d presales, want the published one", len(results.Presales))
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wrestler094/launchpad/internal/storage"
+	"github.com/wrestler094/launchpad/internal/units"
+)
+
+// ParticipationQuote is what a buyTokens call would do at the current raise
+type ParticipationQuote struct {
+	PresaleID                  int                   `json:"presale_id"`
+	Status                     string                `json:"status"` // contributions are accepted while live
+	Amount                     storage.BigInt        `json:"amount"` // base units of the payment currency
+	PaymentToken               string                `json:"payment_token,omitempty"`
+	PaymentDecimals            int                   `json:"payment_decimals"`
+	Rate                       storage.BigInt        `json:"rate"`
+	TokenDecimals              int                   `json:"token_decimals"`
+	Tokens                     storage.BigInt        `json:"tokens"`           // token base units, amount * rate
+	TokensFormatted            string                `json:"tokens_formatted"` // whole tokens
+	RemainingCapacity          storage.BigInt        `json:"remaining_capacity"`
+	RemainingCapacityFormatted string                `json:"remaining_capacity_formatted"`
+	ExceedsCap                 bool                  `json:"exceeds_cap"`              // buyTokens would revert with "Would exceed hard cap"
+	Allocation                 *storage.BigInt       `json:"allocation,omitempty"`     // whitelist allocation of from, 0 for no limit
+	Contributed                storage.BigInt        `json:"contributed"`              // what from has contributed so far
+	ExceedsAllocation          bool                  `json:"exceeds_allocation"`       // buyTokens would revert with "Exceeds allocation"
+	BelowMinContribution       bool                  `json:"below_min_contribution"`   // from's total would stay below min_contribution
+	ExceedsMaxContribution     bool                  `json:"exceeds_max_contribution"` // from's total would pass max_contribution
+	Round                      *storage.PresaleRound `json:"round,omitempty"`          // round the quote prices at, the open one or else the next
+	ExceedsRoundCap            bool                  `json:"exceeds_round_cap"`        // buyTokens would revert with "Would exceed round cap"
+	GasEstimate                *uint64               `json:"gas_estimate"`             // nil when the node could not estimate the call
+	GasError                   string                `json:"gas_error,omitempty"`
+}
+
+// QuoteParticipation quotes a contribution to a presale. The amount is read
+// as in ParticipateRequest: "1.5 ETH", "250 gwei" or "100 USDC", and base
+// units without a unit. from is the buyer address, optional unless the
+// presale is whitelisted. Its existing contributions count towards the
+// per-wallet limits, and it is used for the gas estimate. An address off the
+// whitelist is rejected.
+func (p *PresaleService) QuoteParticipation(presaleID int, amount, from string) (*ParticipationQuote, error) {
+	if from != "" && !common.IsHexAddress(from) {
+		return nil, fmt.Errorf("invalid from address")
+	}
+
+	presale, err := p.presales.GetByID(presaleID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrPresaleNotFound
+		}
+		return nil, fmt.Errorf("failed to get presale: %w", err)
+	}
+
+	// Drafts are not public
+	if presale.Status == storage.PresaleStatusDraft {
+		return nil, ErrPresaleNotFound
+	}
+
+	amountPaid, err := presalePayment(presale, amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	if amountPaid.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount")
+	}
+
+	if presale.Whitelisted && from == "" {
+		return nil, fmt.Errorf("from address is required for a whitelisted presale")
+	}
+
+	entry, err := p.whitelistEntry(presale, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// Multi-round presales price at the open round, or else the next one
+	rate := presale.Rate
+	now := time.Now()
+	round := activeRound(presale.Rounds, now)
+	if round == nil {
+		round = nextRound(presale.Rounds, now)
+	}
+	if round != nil {
+		rate = round.Rate
+	}
+
+	// Same math as Presale.buyTokens: tokens = amount paid * rate
+	tokens := new(big.Int).Mul(amountPaid, rate.Big())
+	remaining := presale.Remaining.Big()
+
+	contributed := new(big.Int)
+	if from != "" {
+		total, err := p.participations.Contributed(presale.ID, from)
+		if err != nil {
+			return nil, err
+		}
+		contributed = total.Big()
+	}
+	walletTotal := new(big.Int).Add(contributed, amountPaid)
+
+	quote := &ParticipationQuote{
+		PresaleID:                  presale.ID,
+		Status:                     presale.Status,
+		Amount:                     storage.NewBigInt(amountPaid),
+		PaymentToken:               presale.PaymentToken,
+		PaymentDecimals:            presale.PaymentDecimals,
+		Rate:                       rate,
+		TokenDecimals:              storage.TokenDecimals,
+		Tokens:                     storage.NewBigInt(tokens),
+		TokensFormatted:            units.FormatUnits(tokens, storage.TokenDecimals),
+		RemainingCapacity:          presale.Remaining,
+		RemainingCapacityFormatted: presale.FormatPaid(remaining),
+		ExceedsCap:                 amountPaid.Cmp(remaining) > 0,
+		Contributed:                storage.NewBigInt(contributed),
+		BelowMinContribution:       walletTotal.Cmp(presale.MinContribution.Big()) < 0,
+		ExceedsMaxContribution:     presale.MaxContribution.Sign() > 0 && walletTotal.Cmp(presale.MaxContribution.Big()) > 0,
+		Round:                      round,
+	}
+
+	if round != nil {
+		left := roundLeft(round)
+		quote.ExceedsRoundCap = left != nil && amountPaid.Cmp(left) > 0
+	}
+
+	if entry != nil {
+		quote.Allocation = &entry.Allocation
+		quote.ExceedsAllocation = entry.Allocation.Sign() > 0 && walletTotal.Cmp(entry.Allocation.Big()) > 0
+	}
+
+	if p.client == nil {
+		quote.GasError = "blockchain client not configured"
+		return quote, nil
+	}
+
+	// A token payment estimates only once from has approved the amount
+	var gas uint64
+	switch {
+	case presale.PaymentToken != "":
+		gas, err = p.client.EstimateTokenBuyTokensGas(common.HexToAddress(presale.Address), common.HexToAddress(from), amountPaid)
+	case entry != nil:
+		proof := make([]common.Hash, len(entry.Proof))
+		for i, sibling := range entry.Proof {
+			proof[i] = common.HexToHash(sibling)
+		}
+		gas, err = p.client.EstimateWhitelistBuyTokensGas(common.HexToAddress(presale.Address), common.HexToAddress(from), amountPaid, entry.Allocation.Big(), proof)
+	default:
+		gas, err = p.client.EstimateBuyTokensGas(common.HexToAddress(presale.Address), common.HexToAddress(from), amountPaid)
+	}
+	if err != nil {
+		quote.GasError = err.Error()
+		return quote, nil
+	}
+	quote.GasEstimate = &gas
+
+	return quote, nil
+}
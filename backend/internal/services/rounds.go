@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/wrestler094/launchpad/internal/storage"
+	"github.com/wrestler094/launchpad/internal/units"
+)
+
+// Errors returned when a contribution does not fit the rounds of a
+// multi-round presale
+var (
+	ErrNoActiveRound   = errors.New("no presale round is open")
+	ErrRoundCapReached = errors.New("contribution exceeds the round cap")
+)
+
+const (
+	maxPresaleRounds   = 10
+	maxRoundNameLength = 50
+)
+
+// PresaleRoundRequest represents a pricing round of a multi-round presale
+type PresaleRoundRequest struct {
+	Name      string `json:"name"`       // "seed", "private", "public"...
+	Rate      string `json:"rate"`       // as the presale rate
+	Cap       string `json:"cap"`        // wei the round may raise, as soft_cap; empty for up to the hard cap
+	StartTime string `json:"start_time"` // ISO 8601 format
+	EndTime   string `json:"end_time"`   // ISO 8601 format
+}
+
+// parseRounds parses the rounds of a presale. Rounds must follow each other
+// without overlapping, end by the deadline and cap at most the hard cap.
+func parseRounds(reqs []PresaleRoundRequest, deadline time.Time, hardCap *big.Int) ([]*storage.PresaleRound, error) {
+	if len(reqs) > maxPresaleRounds {
+		return nil, fmt.Errorf("at most %d rounds are allowed", maxPresaleRounds)
+	}
+
+	rounds := make([]*storage.PresaleRound, 0, len(reqs))
+	for i, req := range reqs {
+		name := strings.TrimSpace(req.Name)
+		if name == "" || len(name) > maxRoundNameLength {
+			return nil, fmt.Errorf("round %d: name must be 1 to %d characters", i+1, maxRoundNameLength)
+		}
+
+		rate, err := units.ParseUnits(req.Rate, 0)
+		if err != nil {
+			return nil, fmt.Errorf("round %s: invalid rate: %w", name, err)
+		}
+		if rate.Sign() <= 0 {
+			return nil, fmt.Errorf("round %s: rate must be positive", name)
+		}
+
+		cap, err := parseOptionalETH(req.Cap)
+		if err != nil {
+			return nil, fmt.Errorf("round %s: invalid cap: %w", name, err)
+		}
+		if cap.Cmp(hardCap) > 0 {
+			return nil, fmt.Errorf("round %s: cap must not exceed hard cap", name)
+		}
+
+		startTime, err := time.Parse(time.RFC3339, req.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("round %s: invalid start time format: %w", name, err)
+		}
+
+		endTime, err := time.Parse(time.RFC3339, req.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("round %s: invalid end time format: %w", name, err)
+		}
+
+		if !startTime.Before(endTime) {
+			return nil, fmt.Errorf("round %s: start time must be before its end time", name)
+		}
+		if endTime.After(deadline) {
+			return nil, fmt.Errorf("round %s: must end by the deadline", name)
+		}
+		if i > 0 && startTime.Before(rounds[i-1].EndTime) {
+			return nil, fmt.Errorf("round %s: must start after round %s ends", name, rounds[i-1].Name)
+		}
+
+		rounds = append(rounds, &storage.PresaleRound{
+			Index:     i,
+			Name:      name,
+			Rate:      storage.NewBigInt(rate),
+			Cap:       storage.NewBigInt(cap),
+			StartTime: startTime.UTC(),
+			EndTime:   endTime.UTC(),
+		})
+	}
+
+	return rounds, nil
+}
+
+// activeRound returns the round open at now, nil between and outside rounds
+func activeRound(rounds []*storage.PresaleRound, now time.Time) *storage.PresaleRound {
+	for _, round := range rounds {
+		if !round.StartTime.After(now) && round.EndTime.After(now) {
+			return round
+		}
+	}
+	return nil
+}
+
+// nextRound returns the first round that opens after now, nil after the last
+func nextRound(rounds []*storage.PresaleRound, now time.Time) *storage.PresaleRound {
+	for _, round := range rounds {
+		if round.StartTime.After(now) {
+			return round
+		}
+	}
+	return nil
+}
+
+// roundLeft returns what is left of a round's cap, nil without a cap
+func roundLeft(round *storage.PresaleRound) *big.Int {
+	if round.Cap.Sign() == 0 {
+		return nil
+	}
+	return new(big.Int).Sub(round.Cap.Big(), round.Raised.Big())
+}
+
+// noActiveRoundError explains that no round is open at now
+func noActiveRoundError(rounds []*storage.PresaleRound, now time.Time) error {
+	if next := nextRound(rounds, now); next != nil {
+		return fmt.Errorf("%w; round %s opens at %s", ErrNoActiveRound, next.Name, next.StartTime.Format(time.RFC3339))
+	}
+	return ErrNoActiveRound
+}
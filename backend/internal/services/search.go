@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+const (
+	minSearchTermLength = 2
+	maxSearchTermLength = 100
+	defaultSearchLimit  = 10
+	maxSearchLimit      = 50
+)
+
+// SearchService handles searching tokens and presales
+type SearchService struct {
+	tokens   storage.TokenRepository
+	presales storage.PresaleRepository
+}
+
+// SearchResponse represents the results of a search
+type SearchResponse struct {
+	Query    string                         `json:"query"`
+	Tokens   []*storage.TokenSearchResult   `json:"tokens"`
+	Presales []*storage.PresaleSearchResult `json:"presales"`
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(tokens storage.TokenRepository, presales storage.PresaleRepository) *SearchService {
+	return &SearchService{
+		tokens:   tokens,
+		presales: presales,
+	}
+}
+
+// Search finds tokens and presales by token name, symbol, address or creator
+func (s *SearchService) Search(term, limitParam string) (*SearchResponse, error) {
+	term = strings.TrimSpace(term)
+	length := utf8.RuneCountInString(term)
+	if length < minSearchTermLength || length > maxSearchTermLength {
+		return nil, fmt.Errorf("q must be between %d and %d characters", minSearchTermLength, maxSearchTermLength)
+	}
+
+	limit := defaultSearchLimit
+	if limitParam != "" {
+		var err error
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxSearchLimit)
+		}
+	}
+
+	tokens, err := s.tokens.Search(term, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	presales, err := s.presales.Search(term, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens == nil {
+		tokens = []*storage.TokenSearchResult{}
+	}
+	if presales == nil {
+		presales = []*storage.PresaleSearchResult{}
+	}
+
+	return &SearchResponse{
+		Query:    term,
+		Tokens:   tokens,
+		Presales: presales,
+	}, nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+// sessionTTL is how long a session and its JWT stay valid
+const sessionTTL = 24 * time.Hour
+
+// sessionTouchInterval limits how often last activity is written per session
+const sessionTouchInterval = time.Minute
+
+// SessionInfo describes the client a session is created for
+type SessionInfo struct {
+	UserAgent  string
+	RemoteAddr string
+}
+
+// SessionResponse represents a session as listed to its owner
+type SessionResponse struct {
+	*storage.Session
+	Current bool `json:"current"`
+}
+
+// createSession stores a new session for an address
+func (a *AuthService) createSession(address string, info SessionInfo) (*storage.Session, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	userAgent := info.UserAgent
+	if len(userAgent) > 512 {
+		userAgent = strings.ToValidUTF8(userAgent[:512], "")
+	}
+
+	// RealIP rewrites RemoteAddr to a bare IP; fall back to host:port parsing
+	ip := info.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	session := &storage.Session{
+		ID:        hex.EncodeToString(bytes),
+		Address:   address,
+		UserAgent: userAgent,
+		IP:        ip,
+		ExpiresAt: time.Now().Add(sessionTTL),
+	}
+
+	query := `
+		INSERT INTO sessions (id, address, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, last_active_at
+	`
+
+	err := a.db.QueryRow(
+		query,
+		session.ID,
+		session.Address,
+		session.UserAgent,
+		session.IP,
+		session.ExpiresAt,
+	).Scan(&session.CreatedAt, &session.LastActiveAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert session: %w", err)
+	}
+
+	return session, nil
+}
+
+// validateSession checks that the session behind a JWT is still active
+// and records activity on it
+func (a *AuthService) validateSession(claims *Claims) error {
+	if claims.ID == "" {
+		return fmt.Errorf("token has no session")
+	}
+
+	query := `
+		SELECT address, expires_at, revoked_at, last_active_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	var (
+		address      string
+		expiresAt    time.Time
+		revokedAt    *time.Time
+		lastActiveAt time.Time
+	)
+	err := a.db.QueryRow(query, claims.ID).Scan(&address, &expiresAt, &revokedAt, &lastActiveAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("session not found")
+		}
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if address != claims.Address {
+		return fmt.Errorf("session does not match token")
+	}
+
+	if revokedAt != nil {
+		return fmt.Errorf("session revoked")
+	}
+
+	if time.Now().After(expiresAt) {
+		return fmt.Errorf("session expired")
+	}
+
+	if time.Since(lastActiveAt) > sessionTouchInterval {
+		_, err := a.db.Exec(`UPDATE sessions SET last_active_at = CURRENT_TIMESTAMP WHERE id = $1`, claims.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListSessions lists the active sessions of an address, marking the current one
+func (a *AuthService) ListSessions(address, currentSessionID string) ([]*SessionResponse, error) {
+	query := `
+		SELECT id, address, user_agent, ip, created_at, last_active_at, expires_at, revoked_at
+		FROM sessions
+		WHERE address = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY last_active_at DESC
+	`
+
+	rows, err := a.db.Query(query, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*SessionResponse
+	for rows.Next() {
+		session := &storage.Session{}
+		err := rows.Scan(
+			&session.ID,
+			&session.Address,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastActiveAt,
+			&session.ExpiresAt,
+			&session.RevokedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, &SessionResponse{
+			Session: session,
+			Current: session.ID == currentSessionID,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one session of an address
+func (a *AuthService) RevokeSession(address, sessionID string) error {
+	query := `
+		UPDATE sessions
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND address = $2 AND revoked_at IS NULL
+	`
+
+	result, err := a.db.Exec(query, sessionID, address)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("session not found")
+	}
+
+	return nil
+}
+
+// RevokeAllSessions revokes every session of an address, optionally keeping one
+func (a *AuthService) RevokeAllSessions(address, keepSessionID string) (int64, error) {
+	query := `
+		UPDATE sessions
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE address = $1 AND id <> $2 AND revoked_at IS NULL
+	`
+
+	result, err := a.db.Exec(query, address, keepSessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return affected, nil
+}
@@ -1,26 +1,26 @@
 package services
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/wrestler094/launchpad/internal/contracts"
 	"github.com/wrestler094/launchpad/internal/storage"
+	"github.com/wrestler094/launchpad/internal/units"
 )
 
 // TokenService handles token-related operations
 type TokenService struct {
 	client *contracts.Client
-	db     *sql.DB
+	tokens storage.TokenRepository
 }
 
 // CreateTokenRequest represents a token creation request
 type CreateTokenRequest struct {
 	Name        string `json:"name"`
 	Symbol      string `json:"symbol"`
-	TotalSupply string `json:"total_supply"`
+	TotalSupply string `json:"total_supply"` // whole tokens, minted with 18 decimals
 }
 
 // CreateTokenResponse represents a token creation response
@@ -30,11 +30,19 @@ type CreateTokenResponse struct {
 	Token   *storage.Token `json:"token"`
 }
 
+// ListTokensRequest represents the query of a token list. Sort is created_at
+// or total_supply.
+type ListTokensRequest struct {
+	PageRequest
+	CreatedFrom string // ISO 8601 format
+	CreatedTo   string // ISO 8601 format
+}
+
 // NewTokenService creates a new token service
-func NewTokenService(client *contracts.Client, db *sql.DB) *TokenService {
+func NewTokenService(client *contracts.Client, tokens storage.TokenRepository) *TokenService {
 	return &TokenService{
 		client: client,
-		db:     db,
+		tokens: tokens,
 	}
 }
 
@@ -50,8 +58,12 @@ func (t *TokenService) CreateToken(creatorAddress string, req *CreateTokenReques
 	}
 
 	// Parse total supply
-	totalSupply, ok := new(big.Int).SetString(req.TotalSupply, 10)
-	if !ok {
+	totalSupply, err := units.ParseUnits(req.TotalSupply, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid total supply: %w", err)
+	}
+
+	if totalSupply.Sign() <= 0 {
 		return nil, fmt.Errorf("invalid total supply")
 	}
 
@@ -67,12 +79,12 @@ func (t *TokenService) CreateToken(creatorAddress string, req *CreateTokenReques
 		Address:        tokenAddress.Hex(),
 		Name:           req.Name,
 		Symbol:         req.Symbol,
-		TotalSupply:    totalSupply.String(),
+		TotalSupply:    storage.NewBigInt(totalSupply),
 		CreatorAddress: creatorAddress,
 		TxHash:         txHash,
 	}
 
-	err := t.storeToken(token)
+	err = t.tokens.Create(token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to store token: %w", err)
 	}
@@ -90,26 +102,9 @@ func (t *TokenService) GetToken(address string) (*storage.Token, error) {
 		return nil, fmt.Errorf("invalid token address")
 	}
 
-	query := `
-		SELECT id, address, name, symbol, total_supply, creator_address, tx_hash, created_at
-		FROM tokens 
-		WHERE address = $1
-	`
-
-	token := &storage.Token{}
-	err := t.db.QueryRow(query, address).Scan(
-		&token.ID,
-		&token.Address,
-		&token.Name,
-		&token.Symbol,
-		&token.TotalSupply,
-		&token.CreatorAddress,
-		&token.TxHash,
-		&token.CreatedAt,
-	)
-
+	token, err := t.tokens.GetByAddress(address)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, fmt.Errorf("token not found")
 		}
 		return nil, fmt.Errorf("failed to get token: %w", err)
@@ -118,68 +113,41 @@ func (t *TokenService) GetToken(address string) (*storage.Token, error) {
 	return token, nil
 }
 
-// ListTokens lists tokens created by a user
-func (t *TokenService) ListTokens(creatorAddress string) ([]*storage.Token, error) {
-	if !common.IsHexAddress(creatorAddress) {
-		return nil, fmt.Errorf("invalid creator address")
+// ListTokens lists a page of tokens created by any of a user's wallets and
+// returns the cursor of the next page, empty on the last page
+func (t *TokenService) ListTokens(creatorAddresses []string, req *ListTokensRequest) ([]*storage.Token, string, error) {
+	if len(creatorAddresses) == 0 {
+		return nil, "", fmt.Errorf("no creator addresses")
 	}
 
-	query := `
-		SELECT id, address, name, symbol, total_supply, creator_address, tx_hash, created_at
-		FROM tokens 
-		WHERE creator_address = $1 
-		ORDER BY created_at DESC
-	`
+	for _, creatorAddress := range creatorAddresses {
+		if !common.IsHexAddress(creatorAddress) {
+			return nil, "", fmt.Errorf("invalid creator address")
+		}
+	}
 
-	rows, err := t.db.Query(query, creatorAddress)
+	page, err := req.pageQuery()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tokens: %w", err)
-	}
-	defer rows.Close()
-
-	var tokens []*storage.Token
-	for rows.Next() {
-		token := &storage.Token{}
-		err := rows.Scan(
-			&token.ID,
-			&token.Address,
-			&token.Name,
-			&token.Symbol,
-			&token.TotalSupply,
-			&token.CreatorAddress,
-			&token.TxHash,
-			&token.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan token: %w", err)
-		}
-		tokens = append(tokens, token)
+		return nil, "", err
 	}
 
-	return tokens, nil
-}
+	query := &storage.TokenQuery{
+		PageQuery:        page,
+		CreatorAddresses: creatorAddresses,
+	}
 
-// storeToken stores a token in the database
-func (t *TokenService) storeToken(token *storage.Token) error {
-	query := `
-		INSERT INTO tokens (address, name, symbol, total_supply, creator_address, tx_hash)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at
-	`
-
-	err := t.db.QueryRow(
-		query,
-		token.Address,
-		token.Name,
-		token.Symbol,
-		token.TotalSupply,
-		token.CreatorAddress,
-		token.TxHash,
-	).Scan(&token.ID, &token.CreatedAt)
+	if query.CreatedFrom, err = parseTimeParam("created_from", req.CreatedFrom); err != nil {
+		return nil, "", err
+	}
+	if query.CreatedTo, err = parseTimeParam("created_to", req.CreatedTo); err != nil {
+		return nil, "", err
+	}
 
+	tokens, next, err := t.tokens.List(query)
 	if err != nil {
-		return fmt.Errorf("failed to insert token: %w", err)
+		return nil, "", err
 	}
 
-	return nil
-}
\ No newline at end of file
+	return tokens, encodeCursor(next), nil
+}
+
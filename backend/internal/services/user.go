@@ -0,0 +1,536 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wrestler094/launchpad/internal/storage"
+)
+
+// User roles
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permission is a named capability granted to one or more roles
+type Permission string
+
+// Permissions checked by route-level middleware
+const (
+	PermissionModerate    Permission = "moderate"
+	PermissionManageRoles Permission = "roles:manage"
+	PermissionViewAudit   Permission = "audit:read"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleUser:      {},
+	RoleModerator: {PermissionModerate},
+	RoleAdmin:     {PermissionModerate, PermissionManageRoles, PermissionViewAudit},
+}
+
+// UserService handles platform users, their roles and linked wallets.
+// An account is identified by the ID of its primary user; linked wallets
+// point at it through account_id.
+type UserService struct {
+	db             *sql.DB
+	adminAddresses map[string]bool
+}
+
+// SetRoleRequest represents a role change request
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// UpdateProfileRequest represents a profile update request.
+// All fields are replaced; empty strings clear a field.
+type UpdateProfileRequest struct {
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+	Bio         string `json:"bio"`
+	Website     string `json:"website"`
+	Twitter     string `json:"twitter"`
+	Telegram    string `json:"telegram"`
+	Discord     string `json:"discord"`
+}
+
+// PublicProfile represents the part of a user's profile shown to investors
+type PublicProfile struct {
+	Address     string `json:"address"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+	Bio         string `json:"bio"`
+	Website     string `json:"website"`
+	Twitter     string `json:"twitter"`
+	Telegram    string `json:"telegram"`
+	Discord     string `json:"discord"`
+}
+
+// Wallet represents an address belonging to an account
+type Wallet struct {
+	Address  string     `json:"address"`
+	Primary  bool       `json:"primary"`
+	LinkedAt *time.Time `json:"linked_at"`
+}
+
+// userColumns lists the columns scanned by scanUser
+const userColumns = `id, COALESCE(account_id, id), address, role, display_name, avatar_url, bio, website,
+		       twitter, telegram, discord, created_at, updated_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// NewUserService creates a new user service
+func NewUserService(db *sql.DB) *UserService {
+	// Addresses listed in ADMIN_ADDRESSES start as admins on their first
+	// login, which bootstraps the first admins of a fresh deployment
+	adminAddresses := make(map[string]bool)
+	for _, address := range strings.Split(os.Getenv("ADMIN_ADDRESSES"), ",") {
+		address = strings.TrimSpace(address)
+		if common.IsHexAddress(address) {
+			adminAddresses[common.HexToAddress(address).Hex()] = true
+		}
+	}
+
+	return &UserService{
+		db:             db,
+		adminAddresses: adminAddresses,
+	}
+}
+
+// UpsertUser records a user on login and returns it with its current role
+func (u *UserService) UpsertUser(address string) (*storage.User, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid user address")
+	}
+
+	role := RoleUser
+	if u.adminAddresses[address] {
+		role = RoleAdmin
+	}
+
+	// The bootstrap role applies to new users only, so a demotion through
+	// SetRole sticks; the no-op update makes RETURNING yield existing rows
+	query := `
+		INSERT INTO users (address, role)
+		VALUES ($1, $2)
+		ON CONFLICT (address) DO UPDATE SET role = users.role
+		RETURNING ` + userColumns
+
+	user, err := scanUser(u.db.QueryRow(query, address, role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetRole gets the current role of an address. Unknown addresses are plain users.
+func (u *UserService) GetRole(address string) (string, error) {
+	query := `SELECT role FROM users WHERE address = $1`
+
+	var role string
+	err := u.db.QueryRow(query, address).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return RoleUser, nil
+		}
+		return "", fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+// HasPermission reports whether the current role of an address grants a permission
+func (u *UserService) HasPermission(address string, permission Permission) (bool, error) {
+	role, err := u.GetRole(address)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ListUsers lists users, optionally filtered by role
+func (u *UserService) ListUsers(role string) ([]*storage.User, error) {
+	if role != "" && !isValidRole(role) {
+		return nil, fmt.Errorf("invalid role")
+	}
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE $1 = '' OR role = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := u.db.Query(query, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*storage.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// SetRole changes the role of an address on behalf of an admin. The role
+// outlasts later logins, also for addresses in ADMIN_ADDRESSES.
+func (u *UserService) SetRole(actorAddress, address string, req *SetRoleRequest) (*storage.User, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid user address")
+	}
+
+	if !isValidRole(req.Role) {
+		return nil, fmt.Errorf("invalid role")
+	}
+
+	address = common.HexToAddress(address).Hex()
+	if address == actorAddress && req.Role != RoleAdmin {
+		return nil, fmt.Errorf("admins cannot demote themselves")
+	}
+
+	query := `
+		INSERT INTO users (address, role)
+		VALUES ($1, $2)
+		ON CONFLICT (address) DO UPDATE SET role = EXCLUDED.role, updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + userColumns
+
+	user, err := scanUser(u.db.QueryRow(query, address, req.Role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set role: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetProfile gets the profile of the account an address belongs to,
+// creating an empty one if needed
+func (u *UserService) GetProfile(address string) (*storage.User, error) {
+	user, err := u.UpsertUser(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.AccountID == user.ID {
+		return user, nil
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+
+	account, err := scanUser(u.db.QueryRow(query, user.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return account, nil
+}
+
+// UpdateProfile replaces the profile fields of the account an address belongs to
+func (u *UserService) UpdateProfile(address string, req *UpdateProfileRequest) (*storage.User, error) {
+	user, err := u.UpsertUser(address)
+	if err != nil {
+		return nil, err
+	}
+
+	req.DisplayName = strings.TrimSpace(req.DisplayName)
+	req.Twitter = strings.TrimPrefix(strings.TrimSpace(req.Twitter), "@")
+	req.Telegram = strings.TrimPrefix(strings.TrimSpace(req.Telegram), "@")
+	req.Discord = strings.TrimSpace(req.Discord)
+
+	if len(req.DisplayName) > 64 {
+		return nil, fmt.Errorf("display_name must be at most 64 characters")
+	}
+
+	if len(req.Bio) > 1000 {
+		return nil, fmt.Errorf("bio must be at most 1000 characters")
+	}
+
+	if err := validateProfileURL("avatar_url", req.AvatarURL); err != nil {
+		return nil, err
+	}
+
+	if err := validateProfileURL("website", req.Website); err != nil {
+		return nil, err
+	}
+
+	if len(req.Twitter) > 64 || len(req.Telegram) > 64 || len(req.Discord) > 64 {
+		return nil, fmt.Errorf("social handles must be at most 64 characters")
+	}
+
+	query := `
+		UPDATE users
+		SET display_name = $2,
+		    avatar_url = $3,
+		    bio = $4,
+		    website = $5,
+		    twitter = $6,
+		    telegram = $7,
+		    discord = $8,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	account, err := scanUser(u.db.QueryRow(
+		query,
+		user.AccountID,
+		req.DisplayName,
+		req.AvatarURL,
+		req.Bio,
+		req.Website,
+		req.Twitter,
+		req.Telegram,
+		req.Discord,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetPublicProfile gets the public profile of the account an address belongs to.
+// Addresses without a profile get one holding only the address.
+func (u *UserService) GetPublicProfile(address string) (*PublicProfile, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = (SELECT COALESCE(account_id, id) FROM users WHERE address = $1)
+	`
+
+	user, err := scanUser(u.db.QueryRow(query, address))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &PublicProfile{Address: address}, nil
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return &PublicProfile{
+		Address:     user.Address,
+		DisplayName: user.DisplayName,
+		AvatarURL:   user.AvatarURL,
+		Bio:         user.Bio,
+		Website:     user.Website,
+		Twitter:     user.Twitter,
+		Telegram:    user.Telegram,
+		Discord:     user.Discord,
+	}, nil
+}
+
+// AccountAddresses lists every wallet address in the account an address
+// belongs to. Addresses that never logged in form an account of their own.
+func (u *UserService) AccountAddresses(address string) ([]string, error) {
+	query := `
+		SELECT address
+		FROM users
+		WHERE COALESCE(account_id, id) = (SELECT COALESCE(account_id, id) FROM users WHERE address = $1)
+		ORDER BY id
+	`
+
+	rows, err := u.db.Query(query, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []string
+	for rows.Next() {
+		var linked string
+		if err := rows.Scan(&linked); err != nil {
+			return nil, fmt.Errorf("failed to scan account address: %w", err)
+		}
+		addresses = append(addresses, linked)
+	}
+
+	if len(addresses) == 0 {
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+// ListWallets lists the wallets of the account an address belongs to
+func (u *UserService) ListWallets(address string) ([]*Wallet, error) {
+	query := `
+		SELECT address, account_id IS NULL, linked_at
+		FROM users
+		WHERE COALESCE(account_id, id) = (SELECT COALESCE(account_id, id) FROM users WHERE address = $1)
+		ORDER BY account_id NULLS FIRST, linked_at
+	`
+
+	rows, err := u.db.Query(query, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	defer rows.Close()
+
+	var wallets []*Wallet
+	for rows.Next() {
+		wallet := &Wallet{}
+		if err := rows.Scan(&wallet.Address, &wallet.Primary, &wallet.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan wallet: %w", err)
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	return wallets, nil
+}
+
+// LinkWallet links an address to an account. The caller must have verified
+// that the address owner consented. Addresses that are the primary wallet of
+// an account with other linked wallets, or that already belong to another
+// account, cannot be linked.
+func (u *UserService) LinkWallet(accountID int, address string) (*storage.User, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO users (address) VALUES ($1)
+		ON CONFLICT (address) DO UPDATE SET address = EXCLUDED.address
+		RETURNING ` + userColumns
+
+	user, err := scanUser(tx.QueryRow(query, address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+
+	if user.AccountID == accountID {
+		return nil, fmt.Errorf("wallet is already linked to this account")
+	}
+
+	if user.AccountID != user.ID {
+		return nil, fmt.Errorf("wallet is linked to another account")
+	}
+
+	var members int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM users WHERE account_id = $1`, user.ID).Scan(&members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check wallet: %w", err)
+	}
+
+	if members > 0 {
+		return nil, fmt.Errorf("wallet is the primary wallet of another account")
+	}
+
+	query = `
+		UPDATE users
+		SET account_id = $2, linked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	user, err = scanUser(tx.QueryRow(query, user.ID, accountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to link wallet: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to link wallet: %w", err)
+	}
+
+	return user, nil
+}
+
+// UnlinkWallet detaches a linked wallet from the account of an address.
+// The primary wallet of an account cannot be unlinked.
+func (u *UserService) UnlinkWallet(accountAddress, address string) error {
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid wallet address")
+	}
+
+	query := `
+		UPDATE users
+		SET account_id = NULL, linked_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE address = $2
+		  AND account_id = (SELECT COALESCE(account_id, id) FROM users WHERE address = $1)
+	`
+
+	result, err := u.db.Exec(query, accountAddress, common.HexToAddress(address).Hex())
+	if err != nil {
+		return fmt.Errorf("failed to unlink wallet: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to unlink wallet: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("linked wallet not found")
+	}
+
+	return nil
+}
+
+// scanUser scans a row selected with userColumns
+func scanUser(row rowScanner) (*storage.User, error) {
+	user := &storage.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.AccountID,
+		&user.Address,
+		&user.Role,
+		&user.DisplayName,
+		&user.AvatarURL,
+		&user.Bio,
+		&user.Website,
+		&user.Twitter,
+		&user.Telegram,
+		&user.Discord,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// validateProfileURL checks that an optional profile field holds an http(s) URL
+func validateProfileURL(field, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if len(value) > 512 {
+		return fmt.Errorf("%s must be at most 512 characters", field)
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL", field)
+	}
+
+	return nil
+}
+
+// isValidRole reports whether a role is known
+func isValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wrestler094/launchpad/internal/storage"
+	"github.com/wrestler094/launchpad/internal/units"
+)
+
+// Errors returned for the vesting of a presale
+var (
+	ErrPresaleNotVesting = errors.New("presale has no vesting schedule")
+	ErrVestingNotStarted = errors.New("tokens vest once the presale is finalized successfully")
+	ErrNothingToClaim    = errors.New("no vested tokens to claim")
+)
+
+// maxVestingSeconds bounds the cliff and the linear release, ten years each
+const maxVestingSeconds = 10 * 365 * 24 * 60 * 60
+
+// VestingRequest represents the vesting schedule of a presale. Purchases are
+// held by the contract and released from the successful finalization (TGE).
+type VestingRequest struct {
+	TGEBps          int   `json:"tge_bps"`          // released at TGE, in basis points of each purchase
+	CliffSeconds    int64 `json:"cliff_seconds"`    // after TGE, before the linear release starts
+	DurationSeconds int64 `json:"duration_seconds"` // of the linear release after the cliff
+}
+
+// VestingPosition represents what an address bought from a vesting presale
+// and how much of it has vested, in token base units
+type VestingPosition struct {
+	PresaleID    int            `json:"presale_id"`
+	Address      string         `json:"address"`
+	Purchased    storage.BigInt `json:"purchased"`
+	Vested       storage.BigInt `json:"vested"`
+	Claimed      storage.BigInt `json:"claimed"`
+	Claimable    storage.BigInt `json:"claimable"`     // vested and not claimed yet
+	Locked       storage.BigInt `json:"locked"`        // not vested yet
+	VestingStart *time.Time     `json:"vesting_start"` // TGE, nil until the presale is finalized successfully
+	VestingEnd   *time.Time     `json:"vesting_end"`   // when everything has vested
+
+	PurchasedFormatted string `json:"purchased_formatted"`
+	VestedFormatted    string `json:"vested_formatted"`
+	ClaimedFormatted   string `json:"claimed_formatted"`
+	ClaimableFormatted string `json:"claimable_formatted"`
+	LockedFormatted    string `json:"locked_formatted"`
+}
+
+// PresaleVesting represents the vesting schedule of a presale and the
+// position of every participant
+type PresaleVesting struct {
+	PresaleID       int                `json:"presale_id"`
+	TGEBps          int                `json:"tge_bps"`
+	CliffSeconds    int64              `json:"cliff_seconds"`
+	DurationSeconds int64              `json:"duration_seconds"`
+	VestingStart    *time.Time         `json:"vesting_start"`
+	VestingEnd      *time.Time         `json:"vesting_end"`
+	Positions       []*VestingPosition `json:"positions"`
+}
+
+// ClaimRequest represents a claim of vested tokens
+type ClaimRequest struct {
+	TxHash string `json:"tx_hash"` // of the VestingPresale.claim call
+}
+
+// ClaimResponse represents a recorded claim and the position after it
+type ClaimResponse struct {
+	Claim    *storage.VestingClaim `json:"claim"`
+	Position *VestingPosition      `json:"position"`
+}
+
+// GetPresaleVesting returns the vesting schedule of a presale with the
+// positions of all its participants. Callers check the creator.
+func (p *PresaleService) GetPresaleVesting(presaleID int) (*PresaleVesting, error) {
+	presale, err := p.vestingPresale(presaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := p.vesting.Balances(presaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start, end := vestingWindow(presale)
+	vesting := &PresaleVesting{
+		PresaleID:       presale.ID,
+		TGEBps:          presale.VestingTGEBps,
+		CliffSeconds:    presale.VestingCliff,
+		DurationSeconds: presale.VestingDuration,
+		VestingStart:    start,
+		VestingEnd:      end,
+		Positions:       make([]*VestingPosition, 0, len(balances)),
+	}
+
+	for _, balance := range balances {
+		vesting.Positions = append(vesting.Positions, vestingPosition(presale, balance, now))
+	}
+
+	return vesting, nil
+}
+
+// GetVestingPosition returns an address's vested, claimable and claimed
+// tokens of a vesting presale
+func (p *PresaleService) GetVestingPosition(presaleID int, address string) (*VestingPosition, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid address")
+	}
+
+	presale, err := p.vestingPresale(presaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := p.vesting.Balance(presaleID, common.HexToAddress(address).Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	return vestingPosition(presale, balance, time.Now()), nil
+}
+
+// ClaimVestedTokens records a claim of everything an address has vested and
+// not claimed yet, which is what VestingPresale.claim transfers
+func (p *PresaleService) ClaimVestedTokens(presaleID int, participantAddress string, req *ClaimRequest) (*ClaimResponse, error) {
+	if !common.IsHexAddress(participantAddress) {
+		return nil, fmt.Errorf("invalid participant address")
+	}
+
+	var position *VestingPosition
+	claim, err := p.vesting.Claim(presaleID, participantAddress, func(presale *storage.Presale, balance *storage.VestingBalance) (*storage.VestingClaim, error) {
+		if !presale.Vesting {
+			return nil, ErrPresaleNotVesting
+		}
+
+		if presale.Status != storage.PresaleStatusFinalizedSuccess {
+			return nil, ErrVestingNotStarted
+		}
+
+		now := time.Now()
+		claimable := vestingPosition(presale, balance, now).Claimable
+		if claimable.Sign() <= 0 {
+			return nil, ErrNothingToClaim
+		}
+
+		balance.Claimed = storage.NewBigInt(new(big.Int).Add(balance.Claimed.Big(), claimable.Big()))
+		position = vestingPosition(presale, balance, now)
+
+		return &storage.VestingClaim{
+			AmountTokens: claimable,
+			TxHash:       req.TxHash,
+		}, nil
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrPresaleNotFound
+		}
+		return nil, err
+	}
+
+	return &ClaimResponse{
+		Claim:    claim,
+		Position: position,
+	}, nil
+}
+
+// vestingPresale gets a presale with a vesting schedule
+func (p *PresaleService) vestingPresale(presaleID int) (*storage.Presale, error) {
+	presale, err := p.GetPresale(presaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drafts are not public
+	if presale.Status == storage.PresaleStatusDraft {
+		return nil, ErrPresaleNotFound
+	}
+
+	if !presale.Vesting {
+		return nil, ErrPresaleNotVesting
+	}
+
+	return presale, nil
+}
+
+// vestingPosition computes the vested, claimable and locked tokens of a
+// balance at now
+func vestingPosition(presale *storage.Presale, balance *storage.VestingBalance, now time.Time) *VestingPosition {
+	purchased := balance.Purchased.Big()
+	vested := vestedTokens(presale, purchased, now)
+
+	claimable := new(big.Int).Sub(vested, balance.Claimed.Big())
+	if claimable.Sign() < 0 {
+		claimable = new(big.Int)
+	}
+	locked := new(big.Int).Sub(purchased, vested)
+
+	start, end := vestingWindow(presale)
+	return &VestingPosition{
+		PresaleID:    presale.ID,
+		Address:      balance.Address,
+		Purchased:    balance.Purchased,
+		Vested:       storage.NewBigInt(vested),
+		Claimed:      balance.Claimed,
+		Claimable:    storage.NewBigInt(claimable),
+		Locked:       storage.NewBigInt(locked),
+		VestingStart: start,
+		VestingEnd:   end,
+
+		PurchasedFormatted: units.FormatUnits(purchased, storage.TokenDecimals),
+		VestedFormatted:    units.FormatUnits(vested, storage.TokenDecimals),
+		ClaimedFormatted:   units.FormatUnits(balance.Claimed.Big(), storage.TokenDecimals),
+		ClaimableFormatted: units.FormatUnits(claimable, storage.TokenDecimals),
+		LockedFormatted:    units.FormatUnits(locked, storage.TokenDecimals),
+	}
+}
+
+// vestedTokens computes the part of a purchase vested at now the way
+// VestingPresale.vestedAmount does: nothing before TGE, tge_bps at TGE, and
+// the rest linearly over the duration once the cliff has passed
+func vestedTokens(presale *storage.Presale, purchased *big.Int, now time.Time) *big.Int {
+	start, _ := vestingWindow(presale)
+	if start == nil {
+		return new(big.Int)
+	}
+
+	elapsed := int64(now.Sub(*start) / time.Second)
+	if elapsed >= presale.VestingCliff+presale.VestingDuration {
+		return new(big.Int).Set(purchased)
+	}
+
+	tge := new(big.Int).Mul(purchased, big.NewInt(int64(presale.VestingTGEBps)))
+	tge.Div(tge, big.NewInt(10000))
+	if elapsed < presale.VestingCliff {
+		return tge
+	}
+
+	linear := new(big.Int).Sub(purchased, tge)
+	linear.Mul(linear, big.NewInt(elapsed-presale.VestingCliff))
+	linear.Div(linear, big.NewInt(presale.VestingDuration))
+
+	return tge.Add(tge, linear)
+}
+
+// vestingWindow returns when a presale's tokens start and finish vesting,
+// nil before it is finalized successfully. The finalization is the last
+// status change of such a presale.
+func vestingWindow(presale *storage.Presale) (*time.Time, *time.Time) {
+	if presale.Status != storage.PresaleStatusFinalizedSuccess {
+		return nil, nil
+	}
+
+	start := presale.StatusUpdatedAt
+	end := start.Add(time.Duration(presale.VestingCliff+presale.VestingDuration) * time.Second)
+	return &start, &end
+}
+
+// validateVesting checks a vesting schedule locks part of each purchase for
+// a bounded time
+func validateVesting(req *VestingRequest) error {
+	if req.TGEBps < 0 || req.TGEBps >= 10000 {
+		return fmt.Errorf("vesting tge_bps must be between 0 and 9999")
+	}
+
+	if req.CliffSeconds < 0 || req.CliffSeconds > maxVestingSeconds {
+		return fmt.Errorf("vesting cliff_seconds must be between 0 and %d", maxVestingSeconds)
+	}
+
+	if req.DurationSeconds < 0 || req.DurationSeconds > maxVestingSeconds {
+		return fmt.Errorf("vesting duration_seconds must be between 0 and %d", maxVestingSeconds)
+	}
+
+	if req.CliffSeconds == 0 && req.DurationSeconds == 0 {
+		return fmt.Errorf("vesting needs a cliff or a duration")
+	}
+
+	return nil
+}
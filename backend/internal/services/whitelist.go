@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wrestler094/launchpad/internal/merkle"
+	"github.com/wrestler094/launchpad/internal/storage"
+	"github.com/wrestler094/launchpad/internal/units"
+)
+
+// Errors returned for the whitelist of a presale
+var (
+	ErrNotWhitelisted        = errors.New("address is not whitelisted")
+	ErrPresaleNotWhitelisted = errors.New("presale has no whitelist")
+	ErrAllocationExceeded    = errors.New("contribution exceeds whitelist allocation")
+	ErrWhitelistLocked       = errors.New("whitelist cannot change once the presale has opened")
+)
+
+const maxWhitelistEntries = 10000
+
+// WhitelistEntryRequest is an address to whitelist
+type WhitelistEntryRequest struct {
+	Address    string `json:"address"`
+	Allocation string `json:"allocation"` // "1.5 ETH", "500 gwei"; wei without a unit; empty for no limit
+}
+
+// UploadWhitelistRequest represents the whitelist of a presale, replacing
+// any whitelist uploaded before
+type UploadWhitelistRequest struct {
+	Entries []WhitelistEntryRequest `json:"entries"`
+}
+
+// UploadWhitelistResponse represents an uploaded whitelist
+type UploadWhitelistResponse struct {
+	PresaleID  int    `json:"presale_id"`
+	MerkleRoot string `json:"merkle_root"` // to set on the presale contract
+	Entries    int    `json:"entries"`
+}
+
+// WhitelistProof is what an address passes to WhitelistPresale.buyTokens
+type WhitelistProof struct {
+	PresaleID           int            `json:"presale_id"`
+	Address             string         `json:"address"`
+	Allocation          storage.BigInt `json:"allocation"` // wei, 0 for no limit
+	AllocationFormatted string         `json:"allocation_formatted"`
+	MerkleRoot          string         `json:"merkle_root"`
+	Proof               []string       `json:"proof"`
+}
+
+// UploadWhitelist builds the Merkle tree of a whitelisted presale's
+// whitelist and stores it with the proof of every address. The whitelist
+// can be replaced until the presale opens. Callers check the creator.
+func (p *PresaleService) UploadWhitelist(presaleID int, req *UploadWhitelistRequest) (*UploadWhitelistResponse, error) {
+	if len(req.Entries) == 0 {
+		return nil, fmt.Errorf("entries are required")
+	}
+	if len(req.Entries) > maxWhitelistEntries {
+		return nil, fmt.Errorf("at most %d entries are allowed", maxWhitelistEntries)
+	}
+
+	entries := make([]*storage.WhitelistEntry, 0, len(req.Entries))
+	leaves := make([]common.Hash, 0, len(req.Entries))
+	seen := make(map[common.Address]bool, len(req.Entries))
+	for _, item := range req.Entries {
+		if !common.IsHexAddress(item.Address) {
+			return nil, fmt.Errorf("invalid address %q", item.Address)
+		}
+
+		address := common.HexToAddress(item.Address)
+		if seen[address] {
+			return nil, fmt.Errorf("duplicate address %s", address.Hex())
+		}
+		seen[address] = true
+
+		allocation, err := parseOptionalETH(item.Allocation)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allocation of %s: %w", address.Hex(), err)
+		}
+
+		entries = append(entries, &storage.WhitelistEntry{
+			Address:    address.Hex(),
+			Allocation: storage.NewBigInt(allocation),
+		})
+		leaves = append(leaves, merkle.AllocationLeaf(address, allocation))
+	}
+
+	tree, err := merkle.New(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, entry := range entries {
+		for _, sibling := range tree.Proof(i) {
+			entry.Proof = append(entry.Proof, sibling.Hex())
+		}
+		if entry.Proof == nil {
+			entry.Proof = []string{}
+		}
+	}
+
+	root := tree.Root().Hex()
+	_, err = p.whitelists.Replace(presaleID, root, entries, func(presale *storage.Presale) error {
+		if !presale.Whitelisted {
+			return ErrPresaleNotWhitelisted
+		}
+
+		switch presale.Status {
+		case storage.PresaleStatusDraft, storage.PresaleStatusUnfunded, storage.PresaleStatusScheduled:
+			return nil
+		default:
+			return ErrWhitelistLocked
+		}
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrPresaleNotFound
+		}
+		return nil, err
+	}
+
+	return &UploadWhitelistResponse{
+		PresaleID:  presaleID,
+		MerkleRoot: root,
+		Entries:    len(entries),
+	}, nil
+}
+
+// GetWhitelistProof returns the allocation and Merkle proof of an address
+// on a presale's whitelist
+func (p *PresaleService) GetWhitelistProof(presaleID int, address string) (*WhitelistProof, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid address")
+	}
+
+	presale, err := p.GetPresale(presaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drafts are not public
+	if presale.Status == storage.PresaleStatusDraft {
+		return nil, ErrPresaleNotFound
+	}
+
+	if !presale.Whitelisted || presale.MerkleRoot == "" {
+		return nil, ErrPresaleNotWhitelisted
+	}
+
+	entry, err := p.whitelists.Entry(presaleID, common.HexToAddress(address).Hex())
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotWhitelisted
+		}
+		return nil, err
+	}
+
+	return &WhitelistProof{
+		PresaleID:           presaleID,
+		Address:             entry.Address,
+		Allocation:          entry.Allocation,
+		AllocationFormatted: units.FormatETH(entry.Allocation.Big()),
+		MerkleRoot:          presale.MerkleRoot,
+		Proof:               entry.Proof,
+	}, nil
+}
+
+// whitelistAllocation checks that an address may contribute to a presale and
+// returns the wei it may contribute in total, nil when it is not limited
+func (p *PresaleService) whitelistAllocation(presale *storage.Presale, address string) (*big.Int, error) {
+	entry, err := p.whitelistEntry(presale, address)
+	if err != nil || entry == nil || entry.Allocation.Sign() == 0 {
+		return nil, err
+	}
+
+	return entry.Allocation.Big(), nil
+}
+
+// whitelistEntry gets the whitelist entry of an address, nil for a presale
+// without a whitelist
+func (p *PresaleService) whitelistEntry(presale *storage.Presale, address string) (*storage.WhitelistEntry, error) {
+	if !presale.Whitelisted {
+		return nil, nil
+	}
+
+	entry, err := p.whitelists.Entry(presale.ID, common.HexToAddress(address).Hex())
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotWhitelisted
+		}
+		return nil, fmt.Errorf("failed to get whitelist entry: %w", err)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/wrestler094/launchpad/internal/units"
+)
+
+// TokenDecimals is the decimals of every launchpad token. MyToken keeps the
+// ERC-20 default and mints total_supply * 10^18 base units, so a rate of N
+// base units per wei sells N whole tokens per ETH.
+const TokenDecimals = 18
+
+// The JSON of the models carries every amount raw, in base units, and
+// formatted next to it, so clients do not have to know which amounts are
+// wei, which are token base units and which are whole tokens.
+
+// MarshalJSON adds the decimals and the supply in base units and formatted
+func (t Token) MarshalJSON() ([]byte, error) {
+	type token Token
+	supply := new(big.Int).Mul(t.TotalSupply.Big(), new(big.Int).Exp(big.NewInt(10), big.NewInt(TokenDecimals), nil))
+
+	return json.Marshal(struct {
+		token
+		Decimals             int    `json:"decimals"`
+		TotalSupplyRaw       BigInt `json:"total_supply_raw"` // base units minted by the contract
+		TotalSupplyFormatted string `json:"total_supply_formatted"`
+	}{
+		token:                token(t),
+		Decimals:             TokenDecimals,
+		TotalSupplyRaw:       NewBigInt(supply),
+		TotalSupplyFormatted: t.TotalSupply.String() + " " + t.Symbol,
+	})
+}
+
+// PaymentUnit returns the currency contributions to a presale are paid in
+func (p *Presale) PaymentUnit() units.Unit {
+	return units.Unit{Symbol: p.PaymentSymbol, Decimals: p.PaymentDecimals}
+}
+
+// FormatPaid formats an amount paid to a presale, such as "1.5 ETH" or
+// "100 USDC"
+func (p *Presale) FormatPaid(amount *big.Int) string {
+	return units.FormatAmount(amount, p.PaymentUnit())
+}
+
+// MarshalJSON adds the rate in whole tokens per whole unit of the payment
+// currency, and the caps, the contribution limits and the raise formatted in
+// the payment currency
+func (p Presale) MarshalJSON() ([]byte, error) {
+	type presale Presale
+	rate := new(big.Int).Mul(p.Rate.Big(), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p.PaymentDecimals)), nil))
+
+	return json.Marshal(struct {
+		presale
+		RateFormatted            string `json:"rate_formatted"`
+		SoftCapFormatted         string `json:"soft_cap_formatted"`
+		HardCapFormatted         string `json:"hard_cap_formatted"`
+		MinContributionFormatted string `json:"min_contribution_formatted"`
+		MaxContributionFormatted string `json:"max_contribution_formatted"`
+		RaisedFormatted          string `json:"raised_formatted"`
+		RemainingFormatted       string `json:"remaining_formatted"`
+	}{
+		presale:                  presale(p),
+		RateFormatted:            units.FormatUnits(rate, TokenDecimals),
+		SoftCapFormatted:         p.FormatPaid(p.SoftCap.Big()),
+		HardCapFormatted:         p.FormatPaid(p.HardCap.Big()),
+		MinContributionFormatted: p.FormatPaid(p.MinContribution.Big()),
+		MaxContributionFormatted: p.FormatPaid(p.MaxContribution.Big()),
+		RaisedFormatted:          p.FormatPaid(p.Raised.Big()),
+		RemainingFormatted:       p.FormatPaid(p.Remaining.Big()),
+	})
+}
+
+// MarshalJSON adds the contribution in the payment currency and the tokens
+// in whole tokens
+func (p PresaleParticipation) MarshalJSON() ([]byte, error) {
+	type participation PresaleParticipation
+	paid := units.Unit{Symbol: p.PaymentSymbol, Decimals: p.PaymentDecimals}
+
+	return json.Marshal(struct {
+		participation
+		AmountPaidFormatted   string `json:"amount_paid_formatted"`
+		AmountTokensFormatted string `json:"amount_tokens_formatted"`
+	}{
+		participation:         participation(p),
+		AmountPaidFormatted:   units.FormatAmount(p.AmountPaid.Big(), paid),
+		AmountTokensFormatted: units.FormatUnits(p.AmountTokens.Big(), TokenDecimals),
+	})
+}
+
+// MarshalJSON adds the allocation formatted in ETH
+func (e WhitelistEntry) MarshalJSON() ([]byte, error) {
+	type entry WhitelistEntry
+
+	return json.Marshal(struct {
+		entry
+		AllocationFormatted string `json:"allocation_formatted"`
+	}{
+		entry:               entry(e),
+		AllocationFormatted: units.FormatETH(e.Allocation.Big()),
+	})
+}
+
+// MarshalJSON adds the claimed tokens in whole tokens
+func (c VestingClaim) MarshalJSON() ([]byte, error) {
+	type claim VestingClaim
+
+	return json.Marshal(struct {
+		claim
+		AmountTokensFormatted string `json:"amount_tokens_formatted"`
+	}{
+		claim:                 claim(c),
+		AmountTokensFormatted: units.FormatUnits(c.AmountTokens.Big(), TokenDecimals),
+	})
+}
+
+// MarshalJSON adds the cap and the raise formatted in ETH
+func (r PresaleRound) MarshalJSON() ([]byte, error) {
+	type round PresaleRound
+
+	return json.Marshal(struct {
+		round
+		CapFormatted    string `json:"cap_formatted"`
+		RaisedFormatted string `json:"raised_formatted"`
+	}{
+		round:           round(r),
+		CapFormatted:    units.FormatETH(r.Cap.Big()),
+		RaisedFormatted: units.FormatETH(r.Raised.Big()),
+	})
+}
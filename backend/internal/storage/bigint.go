@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// BigInt is an integer amount such as a wei value or token supply. It is
+// stored in NUMERIC(78,0) columns and encoded in JSON as a decimal string,
+// since uint256 values do not fit in a JSON number. The zero value is 0.
+//
+// A BigInt is treated as immutable: arithmetic goes through Big, which
+// returns a copy.
+type BigInt struct {
+	i *big.Int
+}
+
+// NewBigInt creates a BigInt holding a copy of x
+func NewBigInt(x *big.Int) BigInt {
+	if x == nil {
+		return BigInt{}
+	}
+	return BigInt{i: new(big.Int).Set(x)}
+}
+
+// ParseBigInt parses a base-10 integer
+func ParseBigInt(s string) (BigInt, bool) {
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return BigInt{}, false
+	}
+	return BigInt{i: x}, true
+}
+
+// Big returns the value as a new big.Int
+func (b BigInt) Big() *big.Int {
+	if b.i == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(b.i)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the value
+func (b BigInt) Sign() int {
+	if b.i == nil {
+		return 0
+	}
+	return b.i.Sign()
+}
+
+// Cmp compares b and other
+func (b BigInt) Cmp(other BigInt) int {
+	return b.Big().Cmp(other.Big())
+}
+
+// String returns the value in base 10
+func (b BigInt) String() string {
+	if b.i == nil {
+		return "0"
+	}
+	return b.i.String()
+}
+
+// Value implements driver.Valuer
+func (b BigInt) Value() (driver.Value, error) {
+	return b.String(), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns
+func (b *BigInt) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		b.i = nil
+		return nil
+	case int64:
+		b.i = big.NewInt(v)
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("cannot scan %T into BigInt", src)
+	}
+
+	// NUMERIC results of integer arithmetic may carry a zero fraction
+	if whole, fraction, ok := strings.Cut(s, "."); ok && strings.Trim(fraction, "0") == "" {
+		s = whole
+	}
+
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("cannot scan %q into BigInt", s)
+	}
+	b.i = x
+	return nil
+}
+
+// MarshalJSON encodes the value as a decimal string
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON integer
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid integer %s", data)
+	}
+	b.i = x
+	return nil
+}
@@ -0,0 +1,778 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStore holds the records shared by the in-memory repositories
+type memoryStore struct {
+	mu             sync.RWMutex
+	tokens         []*Token
+	presales       []*Presale
+	participations []*PresaleParticipation
+	statusHistory  []*PresaleStatusChange
+	whitelists     map[int][]*WhitelistEntry // by presale ID
+	vestingClaims  []*VestingClaim
+	rounds         map[int][]*PresaleRound // by presale ID
+}
+
+// NewMemoryRepositories creates repositories that keep records in memory.
+// They are meant for tests and local experiments; nothing is persisted.
+func NewMemoryRepositories() *Repositories {
+	store := &memoryStore{
+		whitelists: make(map[int][]*WhitelistEntry),
+		rounds:     make(map[int][]*PresaleRound),
+	}
+	return &Repositories{
+		Tokens:         &MemoryTokenRepository{store: store},
+		Presales:       &MemoryPresaleRepository{store: store},
+		Participations: &MemoryParticipationRepository{store: store},
+		Whitelists:     &MemoryWhitelistRepository{store: store},
+		Vesting:        &MemoryVestingRepository{store: store},
+	}
+}
+
+// MemoryTokenRepository is an in-memory TokenRepository
+type MemoryTokenRepository struct {
+	store *memoryStore
+}
+
+// Create stores a token
+func (r *MemoryTokenRepository) Create(token *Token) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.tokens {
+		if existing.Address == token.Address {
+			return fmt.Errorf("failed to insert token: address %s already exists", token.Address)
+		}
+	}
+
+	token.ID = len(r.store.tokens) + 1
+	token.CreatedAt = time.Now()
+
+	stored := *token
+	r.store.tokens = append(r.store.tokens, &stored)
+	return nil
+}
+
+// GetByAddress gets a token by contract address
+func (r *MemoryTokenRepository) GetByAddress(address string) (*Token, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	token := r.store.tokenByAddress(address)
+	if token == nil {
+		return nil, ErrNotFound
+	}
+
+	return token, nil
+}
+
+// Exists reports whether a token with the address is stored
+func (r *MemoryTokenRepository) Exists(address string) (bool, error) {
+	_, err := r.GetByAddress(address)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List returns a page of tokens
+func (r *MemoryTokenRepository) List(query *TokenQuery) ([]*Token, *Cursor, error) {
+	column, err := query.pageColumn(tokenSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tokens []*Token
+	for _, token := range r.store.tokens {
+		if query.CreatorAddresses != nil && !containsAddress(query.CreatorAddresses, token.CreatorAddress) {
+			continue
+		}
+		if !inRange(token.CreatedAt, query.CreatedFrom, query.CreatedTo) {
+			continue
+		}
+		if !query.isAfter(column.kind, tokenSortValue(token, query.Sort), token.ID) {
+			continue
+		}
+		found := *token
+		tokens = append(tokens, &found)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return query.less(column.kind,
+			tokenSortValue(tokens[i], query.Sort), tokens[i].ID,
+			tokenSortValue(tokens[j], query.Sort), tokens[j].ID)
+	})
+
+	tokens, next := tokenPage(&query.PageQuery, tokens)
+	return tokens, next, nil
+}
+
+// Search finds tokens by name, symbol, address or creator
+func (r *MemoryTokenRepository) Search(term string, limit int) ([]*TokenSearchResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var results []*TokenSearchResult
+	for i := len(r.store.tokens) - 1; i >= 0; i-- {
+		token := r.store.tokens[i]
+		if hit, ok := memoryTokenHit(token, term); ok {
+			found := *token
+			results = append(results, &TokenSearchResult{Token: &found, SearchHit: hit})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return searchHitLess(&results[i].SearchHit, &results[j].SearchHit)
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// MemoryPresaleRepository is an in-memory PresaleRepository
+type MemoryPresaleRepository struct {
+	store *memoryStore
+}
+
+// Create stores a presale and starts its status history
+func (r *MemoryPresaleRepository) Create(presale *Presale) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	presale.ID = len(r.store.presales) + 1
+	presale.CreatedAt = time.Now()
+	presale.StatusUpdatedAt = presale.CreatedAt
+
+	stored := *presale
+	stored.Rounds = nil
+	r.store.presales = append(r.store.presales, &stored)
+
+	for _, round := range presale.Rounds {
+		round.PresaleID = presale.ID
+		copied := *round
+		r.store.rounds[presale.ID] = append(r.store.rounds[presale.ID], &copied)
+	}
+
+	r.store.addStatusChange(&PresaleStatusChange{
+		PresaleID:    presale.ID,
+		ToStatus:     presale.Status,
+		ActorAddress: presale.CreatorAddress,
+		Reason:       "created",
+	})
+	return nil
+}
+
+// GetByID gets a presale by ID
+func (r *MemoryPresaleRepository) GetByID(id int) (*Presale, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if id <= 0 || id > len(r.store.presales) {
+		return nil, ErrNotFound
+	}
+
+	presale := r.store.presaleWithStats(r.store.presales[id-1])
+	presale.Rounds = r.store.presaleRounds(id)
+	return presale, nil
+}
+
+// List returns a page of presales
+func (r *MemoryPresaleRepository) List(query *PresaleQuery) ([]*Presale, *Cursor, error) {
+	column, err := query.pageColumn(presaleSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, status := range query.Statuses {
+		if !IsPresaleStatus(status) {
+			return nil, nil, fmt.Errorf("invalid status %q", status)
+		}
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var presales []*Presale
+	for _, presale := range r.store.presales {
+		if query.CreatorAddresses != nil && !containsAddress(query.CreatorAddresses, presale.CreatorAddress) {
+			continue
+		}
+		if query.TokenAddress != "" && presale.TokenAddress != query.TokenAddress {
+			continue
+		}
+		if !inRange(presale.CreatedAt, query.CreatedFrom, query.CreatedTo) ||
+			!inRange(presale.Deadline, query.DeadlineFrom, query.DeadlineTo) ||
+			!inRange(presale.StatusUpdatedAt, query.StatusFrom, nil) {
+			continue
+		}
+		found := r.store.presaleWithStats(presale)
+		if len(query.Statuses) > 0 && !containsString(query.Statuses, found.Status) {
+			continue
+		}
+		if found.ProgressBps < query.MinProgressBps {
+			continue
+		}
+		if !query.isAfter(column.kind, presaleSortValue(found, query.Sort), found.ID) {
+			continue
+		}
+		if query.IncludeToken {
+			token := r.store.tokenByAddress(found.TokenAddress)
+			if token == nil {
+				continue
+			}
+			found.Token = token
+		}
+		presales = append(presales, found)
+	}
+
+	sort.Slice(presales, func(i, j int) bool {
+		return query.less(column.kind,
+			presaleSortValue(presales[i], query.Sort), presales[i].ID,
+			presaleSortValue(presales[j], query.Sort), presales[j].ID)
+	})
+
+	presales, next := presalePage(&query.PageQuery, presales)
+	return presales, next, nil
+}
+
+// Search finds presales by their addresses or their token, leaving out drafts
+func (r *MemoryPresaleRepository) Search(term string, limit int) ([]*PresaleSearchResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var results []*PresaleSearchResult
+	for i := len(r.store.presales) - 1; i >= 0; i-- {
+		presale := r.store.presales[i]
+		if presale.Status == PresaleStatusDraft {
+			continue
+		}
+
+		token := r.store.tokenByAddress(presale.TokenAddress)
+		if token == nil {
+			continue
+		}
+
+		hit, ok := memoryTokenHit(token, term)
+		if strings.EqualFold(presale.Address, term) || strings.EqualFold(presale.CreatorAddress, term) {
+			hit.ExactMatch, hit.Rank, ok = true, 1, true
+		}
+		if !ok {
+			continue
+		}
+
+		found := r.store.presaleWithStats(presale)
+		found.Token = token
+		results = append(results, &PresaleSearchResult{Presale: found, SearchHit: hit})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return searchHitLess(&results[i].SearchHit, &results[j].SearchHit)
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// Transition changes a presale's status while holding the store lock
+func (r *MemoryPresaleRepository) Transition(change *PresaleStatusChange, check TransitionFunc) (*Presale, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if change.PresaleID <= 0 || change.PresaleID > len(r.store.presales) {
+		return nil, ErrNotFound
+	}
+
+	stored := r.store.presales[change.PresaleID-1]
+	presale := r.store.presaleWithStats(stored)
+	if err := check(presale); err != nil {
+		return nil, err
+	}
+	change.FromStatus = presale.Status
+
+	stored.Status = change.ToStatus
+	stored.StatusUpdatedAt = time.Now()
+	r.store.addStatusChange(change)
+
+	return r.store.presaleWithStats(stored), nil
+}
+
+// StatusHistory lists the status changes of a presale, oldest first
+func (r *MemoryPresaleRepository) StatusHistory(presaleID int) ([]*PresaleStatusChange, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var changes []*PresaleStatusChange
+	for _, change := range r.store.statusHistory {
+		if change.PresaleID == presaleID {
+			found := *change
+			changes = append(changes, &found)
+		}
+	}
+
+	return changes, nil
+}
+
+// MemoryParticipationRepository is an in-memory ParticipationRepository
+type MemoryParticipationRepository struct {
+	store *memoryStore
+}
+
+// Reserve stores a participation while holding the store lock
+func (r *MemoryParticipationRepository) Reserve(presaleID int, participantAddress string, reserve ReserveFunc) (*PresaleParticipation, BigInt, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if presaleID <= 0 || presaleID > len(r.store.presales) {
+		return nil, BigInt{}, ErrNotFound
+	}
+
+	presale := r.store.presaleWithStats(r.store.presales[presaleID-1])
+	presale.Rounds = r.store.presaleRounds(presaleID)
+	remaining := presale.Remaining.Big()
+
+	contributed := r.store.contributed(presaleID, participantAddress)
+
+	participation, err := reserve(presale, new(big.Int).Set(remaining), contributed)
+	if err != nil {
+		return nil, BigInt{}, err
+	}
+
+	participation.PresaleID = presaleID
+	participation.ParticipantAddr = participantAddress
+	participation.ID = len(r.store.participations) + 1
+	participation.CreatedAt = time.Now()
+	participation.PaymentToken = presale.PaymentToken
+	participation.PaymentSymbol = presale.PaymentSymbol
+	participation.PaymentDecimals = presale.PaymentDecimals
+
+	stored := *participation
+	r.store.participations = append(r.store.participations, &stored)
+
+	return participation, NewBigInt(remaining.Sub(remaining, participation.AmountPaid.Big())), nil
+}
+
+// Contributed sums the contributions of an address to a presale
+func (r *MemoryParticipationRepository) Contributed(presaleID int, participantAddress string) (BigInt, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return NewBigInt(r.store.contributed(presaleID, participantAddress)), nil
+}
+
+// ListByParticipants lists participations made by any of the addresses
+func (r *MemoryParticipationRepository) ListByParticipants(participantAddresses []string) ([]*PresaleParticipation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var participations []*PresaleParticipation
+	for i := len(r.store.participations) - 1; i >= 0; i-- {
+		participation := r.store.participations[i]
+		if containsAddress(participantAddresses, participation.ParticipantAddr) {
+			found := *participation
+			participations = append(participations, &found)
+		}
+	}
+
+	return participations, nil
+}
+
+// Portfolio sums the participations of the addresses per presale
+func (r *MemoryParticipationRepository) Portfolio(participantAddresses []string) ([]*PortfolioPosition, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byPresale := make(map[int]*PortfolioPosition)
+	var positions []*PortfolioPosition
+	for _, participation := range r.store.participations {
+		if !containsAddress(participantAddresses, participation.ParticipantAddr) {
+			continue
+		}
+
+		position, ok := byPresale[participation.PresaleID]
+		if !ok {
+			presale := r.store.presaleWithStats(r.store.presales[participation.PresaleID-1])
+			presale.Token = r.store.tokenByAddress(presale.TokenAddress)
+			if presale.Token == nil {
+				continue
+			}
+			position = &PortfolioPosition{
+				Presale:            presale,
+				FirstContributedAt: participation.CreatedAt,
+			}
+			byPresale[participation.PresaleID] = position
+			positions = append(positions, position)
+		}
+
+		position.Contributed = NewBigInt(new(big.Int).Add(position.Contributed.Big(), participation.AmountPaid.Big()))
+		position.TokensReceived = NewBigInt(new(big.Int).Add(position.TokensReceived.Big(), participation.AmountTokens.Big()))
+		position.Contributions++
+		position.LastContributedAt = participation.CreatedAt
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].LastContributedAt.After(positions[j].LastContributedAt)
+	})
+
+	return positions, nil
+}
+
+// Contributors sums the participations of a presale per address
+func (r *MemoryParticipationRepository) Contributors(query *ContributorQuery) ([]*Contributor, error) {
+	if _, ok := contributorSortColumns[query.Sort]; !ok {
+		return nil, fmt.Errorf("invalid sort %q", query.Sort)
+	}
+	if query.Order != OrderAsc && query.Order != OrderDesc {
+		return nil, fmt.Errorf("invalid order %q", query.Order)
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byAddress := make(map[string]*Contributor)
+	var contributors []*Contributor
+	raised := new(big.Int)
+	for _, participation := range r.store.participations {
+		if participation.PresaleID != query.PresaleID {
+			continue
+		}
+
+		contributor, ok := byAddress[participation.ParticipantAddr]
+		if !ok {
+			contributor = &Contributor{
+				Address:            participation.ParticipantAddr,
+				FirstContributedAt: participation.CreatedAt,
+			}
+			byAddress[participation.ParticipantAddr] = contributor
+			contributors = append(contributors, contributor)
+		}
+
+		contributor.Contributed = NewBigInt(new(big.Int).Add(contributor.Contributed.Big(), participation.AmountPaid.Big()))
+		contributor.TokensReceived = NewBigInt(new(big.Int).Add(contributor.TokensReceived.Big(), participation.AmountTokens.Big()))
+		contributor.Contributions++
+		contributor.LastContributedAt = participation.CreatedAt
+		raised.Add(raised, participation.AmountPaid.Big())
+	}
+
+	// Rank by amount, ties sharing a rank like SQL RANK()
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].Contributed.Cmp(contributors[j].Contributed) > 0
+	})
+	for i, contributor := range contributors {
+		contributor.Rank = i + 1
+		if i > 0 && contributor.Contributed.Cmp(contributors[i-1].Contributed) == 0 {
+			contributor.Rank = contributors[i-1].Rank
+		}
+		if raised.Sign() > 0 {
+			share := new(big.Int).Mul(contributor.Contributed.Big(), big.NewInt(10000))
+			contributor.ShareBps = int(share.Div(share, raised).Int64())
+		}
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		cmp := compareContributors(contributors[i], contributors[j], query.Sort)
+		if cmp == 0 {
+			return contributors[i].Rank < contributors[j].Rank
+		}
+		if query.Order == OrderDesc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
+	if len(contributors) > query.Limit {
+		contributors = contributors[:query.Limit]
+	}
+
+	return contributors, nil
+}
+
+// MemoryWhitelistRepository is an in-memory WhitelistRepository
+type MemoryWhitelistRepository struct {
+	store *memoryStore
+}
+
+// Replace stores the whitelist of a presale while holding the store lock
+func (r *MemoryWhitelistRepository) Replace(presaleID int, root string, entries []*WhitelistEntry, check TransitionFunc) (*Presale, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if presaleID <= 0 || presaleID > len(r.store.presales) {
+		return nil, ErrNotFound
+	}
+
+	stored := r.store.presales[presaleID-1]
+	if err := check(r.store.presaleWithStats(stored)); err != nil {
+		return nil, err
+	}
+
+	whitelist := make([]*WhitelistEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.PresaleID = presaleID
+		copied := *entry
+		whitelist = append(whitelist, &copied)
+	}
+	r.store.whitelists[presaleID] = whitelist
+	stored.MerkleRoot = root
+
+	return r.store.presaleWithStats(stored), nil
+}
+
+// Entry gets the whitelist entry of an address
+func (r *MemoryWhitelistRepository) Entry(presaleID int, address string) (*WhitelistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, entry := range r.store.whitelists[presaleID] {
+		if entry.Address == address {
+			found := *entry
+			return &found, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// MemoryVestingRepository is an in-memory VestingRepository
+type MemoryVestingRepository struct {
+	store *memoryStore
+}
+
+// Balances sums the tokens bought from a presale and claimed per address
+func (r *MemoryVestingRepository) Balances(presaleID int) ([]*VestingBalance, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var balances []*VestingBalance
+	seen := make(map[string]bool)
+	for _, participation := range r.store.participations {
+		if participation.PresaleID != presaleID || seen[participation.ParticipantAddr] {
+			continue
+		}
+		seen[participation.ParticipantAddr] = true
+		balances = append(balances, r.store.vestingBalance(presaleID, participation.ParticipantAddr))
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if c := balances[i].Purchased.Cmp(balances[j].Purchased); c != 0 {
+			return c > 0
+		}
+		return balances[i].Address < balances[j].Address
+	})
+
+	return balances, nil
+}
+
+// Balance sums the tokens an address bought from a presale and claimed
+func (r *MemoryVestingRepository) Balance(presaleID int, address string) (*VestingBalance, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.vestingBalance(presaleID, address), nil
+}
+
+// Claim stores a claim while holding the store lock
+func (r *MemoryVestingRepository) Claim(presaleID int, participantAddress string, claim ClaimFunc) (*VestingClaim, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if presaleID <= 0 || presaleID > len(r.store.presales) {
+		return nil, ErrNotFound
+	}
+
+	presale := r.store.presaleWithStats(r.store.presales[presaleID-1])
+	stored, err := claim(presale, r.store.vestingBalance(presaleID, participantAddress))
+	if err != nil {
+		return nil, err
+	}
+
+	stored.PresaleID = presaleID
+	stored.ParticipantAddr = participantAddress
+	stored.ID = len(r.store.vestingClaims) + 1
+	stored.CreatedAt = time.Now()
+
+	copied := *stored
+	r.store.vestingClaims = append(r.store.vestingClaims, &copied)
+
+	return stored, nil
+}
+
+// tokenByAddress returns a copy of a stored token, or nil. The caller holds the lock.
+func (s *memoryStore) tokenByAddress(address string) *Token {
+	for _, token := range s.tokens {
+		if token.Address == address {
+			found := *token
+			return &found
+		}
+	}
+	return nil
+}
+
+// presaleWithStats copies a presale and fills in its contribution aggregates
+// the way the Postgres repository computes them. The caller holds the lock.
+func (s *memoryStore) presaleWithStats(presale *Presale) *Presale {
+	found := *presale
+
+	raised := new(big.Int)
+	contributors := make(map[string]struct{})
+	for _, participation := range s.participations {
+		if participation.PresaleID == presale.ID {
+			raised.Add(raised, participation.AmountPaid.Big())
+			contributors[participation.ParticipantAddr] = struct{}{}
+		}
+	}
+
+	found.Raised = NewBigInt(raised)
+	found.Remaining = NewBigInt(new(big.Int).Sub(presale.HardCap.Big(), raised))
+	if found.Remaining.Sign() < 0 {
+		found.Remaining = BigInt{}
+	}
+	found.Contributors = len(contributors)
+	found.Status = PresaleStatus(presale.Status, found.Raised, presale.HardCap, presale.StartTime, presale.Deadline, time.Now())
+	found.ProgressBps = 0
+	if hardCap := presale.HardCap.Big(); hardCap.Sign() > 0 {
+		progress := new(big.Int).Div(new(big.Int).Mul(raised, big.NewInt(10000)), hardCap)
+		if progress.IsInt64() && progress.Int64() <= math.MaxInt32 {
+			found.ProgressBps = int(progress.Int64())
+		} else {
+			found.ProgressBps = math.MaxInt32
+		}
+	}
+
+	return &found
+}
+
+// presaleRounds copies the rounds of a presale and fills in their raise the
+// way the Postgres repository computes it. The caller holds the lock.
+func (s *memoryStore) presaleRounds(presaleID int) []*PresaleRound {
+	now := time.Now()
+	var rounds []*PresaleRound
+	for _, round := range s.rounds[presaleID] {
+		found := *round
+		raised := new(big.Int)
+		contributors := make(map[string]struct{})
+		for _, participation := range s.participations {
+			if participation.PresaleID == presaleID && participation.RoundIndex != nil && *participation.RoundIndex == round.Index {
+				raised.Add(raised, participation.AmountPaid.Big())
+				contributors[participation.ParticipantAddr] = struct{}{}
+			}
+		}
+		setRoundStats(&found, raised, len(contributors), now)
+		rounds = append(rounds, &found)
+	}
+	return rounds
+}
+
+// contributed sums the contributions of an address to a presale. The caller
+// holds the lock.
+func (s *memoryStore) contributed(presaleID int, participantAddress string) *big.Int {
+	total := new(big.Int)
+	for _, participation := range s.participations {
+		if participation.PresaleID == presaleID && participation.ParticipantAddr == participantAddress {
+			total.Add(total, participation.AmountPaid.Big())
+		}
+	}
+	return total
+}
+
+// vestingBalance sums the tokens an address bought from a presale and
+// claimed. The caller holds the lock.
+func (s *memoryStore) vestingBalance(presaleID int, address string) *VestingBalance {
+	purchased, claimed := new(big.Int), new(big.Int)
+	for _, participation := range s.participations {
+		if participation.PresaleID == presaleID && participation.ParticipantAddr == address {
+			purchased.Add(purchased, participation.AmountTokens.Big())
+		}
+	}
+	for _, claim := range s.vestingClaims {
+		if claim.PresaleID == presaleID && claim.ParticipantAddr == address {
+			claimed.Add(claimed, claim.AmountTokens.Big())
+		}
+	}
+
+	return &VestingBalance{
+		Address:   address,
+		Purchased: NewBigInt(purchased),
+		Claimed:   NewBigInt(claimed),
+	}
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// addStatusChange appends a status change to the history, filling in its
+// ID and CreatedAt. Callers hold the write lock.
+func (s *memoryStore) addStatusChange(change *PresaleStatusChange) {
+	change.ID = int64(len(s.statusHistory) + 1)
+	change.CreatedAt = time.Now()
+
+	stored := *change
+	s.statusHistory = append(s.statusHistory, &stored)
+}
+
+// inRange reports whether t is within [from, to); nil bounds are open
+func inRange(t time.Time, from, to *time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if to != nil && !t.Before(*to) {
+		return false
+	}
+	return true
+}
+
+// compareContributors compares two contributors by a sort key
+func compareContributors(a, b *Contributor, sort string) int {
+	switch sort {
+	case SortContributions:
+		return a.Contributions - b.Contributions
+	case SortFirstContributedAt:
+		return a.FirstContributedAt.Compare(b.FirstContributedAt)
+	case SortLastContributedAt:
+		return a.LastContributedAt.Compare(b.LastContributedAt)
+	default:
+		return a.Contributed.Cmp(b.Contributed)
+	}
+}
+
+// searchHitLess orders exact matches first, then by rank
+func searchHitLess(a, b *SearchHit) bool {
+	if a.ExactMatch != b.ExactMatch {
+		return a.ExactMatch
+	}
+	return a.Rank > b.Rank
+}
+
+// containsAddress reports whether address is in addresses
+func containsAddress(addresses []string, address string) bool {
+	for _, candidate := range addresses {
+		if candidate == address {
+			return true
+		}
+	}
+	return false
+}
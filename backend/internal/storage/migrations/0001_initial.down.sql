@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS presale_participations;
+DROP TABLE IF EXISTS presales;
+DROP TABLE IF EXISTS tokens;
+DROP TABLE IF EXISTS users;
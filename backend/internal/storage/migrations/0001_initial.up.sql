@@ -0,0 +1,49 @@
+-- Core schema. IF NOT EXISTS lets databases created by the old
+-- RunMigrations statement list adopt the versioned history.
+CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	address VARCHAR(42) UNIQUE NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tokens (
+	id SERIAL PRIMARY KEY,
+	address VARCHAR(42) UNIQUE NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	symbol VARCHAR(10) NOT NULL,
+	total_supply VARCHAR(255) NOT NULL,
+	creator_address VARCHAR(42) NOT NULL,
+	tx_hash VARCHAR(66) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS presales (
+	id SERIAL PRIMARY KEY,
+	address VARCHAR(42) UNIQUE NOT NULL,
+	token_address VARCHAR(42) NOT NULL,
+	creator_address VARCHAR(42) NOT NULL,
+	rate VARCHAR(255) NOT NULL,
+	soft_cap VARCHAR(255) NOT NULL,
+	hard_cap VARCHAR(255) NOT NULL,
+	deadline TIMESTAMP NOT NULL,
+	tx_hash VARCHAR(66) NOT NULL,
+	active BOOLEAN DEFAULT true,
+	finalized BOOLEAN DEFAULT false,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS presale_participations (
+	id SERIAL PRIMARY KEY,
+	presale_id INTEGER NOT NULL REFERENCES presales(id),
+	participant_address VARCHAR(42) NOT NULL,
+	amount_eth VARCHAR(255) NOT NULL,
+	amount_tokens VARCHAR(255) NOT NULL,
+	tx_hash VARCHAR(66) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tokens_creator ON tokens(creator_address);
+CREATE INDEX IF NOT EXISTS idx_presales_creator ON presales(creator_address);
+CREATE INDEX IF NOT EXISTS idx_presales_token ON presales(token_address);
+CREATE INDEX IF NOT EXISTS idx_participations_presale ON presale_participations(presale_id);
+CREATE INDEX IF NOT EXISTS idx_participations_participant ON presale_participations(participant_address);
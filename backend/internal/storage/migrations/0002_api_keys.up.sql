@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+	id SERIAL PRIMARY KEY,
+	owner_address VARCHAR(42) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	key_prefix VARCHAR(16) NOT NULL,
+	key_hash VARCHAR(64) UNIQUE NOT NULL,
+	scopes TEXT[] NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	last_used_at TIMESTAMP,
+	revoked_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_owner ON api_keys(owner_address);
@@ -0,0 +1,9 @@
+ALTER TABLE users
+	DROP COLUMN IF EXISTS display_name,
+	DROP COLUMN IF EXISTS avatar_url,
+	DROP COLUMN IF EXISTS bio,
+	DROP COLUMN IF EXISTS website,
+	DROP COLUMN IF EXISTS twitter,
+	DROP COLUMN IF EXISTS telegram,
+	DROP COLUMN IF EXISTS discord,
+	DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,9 @@
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS display_name VARCHAR(64) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(512) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS bio VARCHAR(1000) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS website VARCHAR(512) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS twitter VARCHAR(64) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS telegram VARCHAR(64) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS discord VARCHAR(64) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
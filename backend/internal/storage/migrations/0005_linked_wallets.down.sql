@@ -0,0 +1,3 @@
+ALTER TABLE users
+	DROP COLUMN IF EXISTS account_id,
+	DROP COLUMN IF EXISTS linked_at;
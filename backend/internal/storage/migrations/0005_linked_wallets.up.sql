@@ -0,0 +1,5 @@
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS account_id INTEGER REFERENCES users(id),
+	ADD COLUMN IF NOT EXISTS linked_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_account ON users(account_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS sessions (
+	id VARCHAR(64) PRIMARY KEY,
+	address VARCHAR(42) NOT NULL,
+	user_agent VARCHAR(512) NOT NULL,
+	ip VARCHAR(64) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	last_active_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_address ON sessions(address);
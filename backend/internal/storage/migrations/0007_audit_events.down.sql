@@ -0,0 +1,3 @@
+-- DROP TABLE does not fire the TRUNCATE trigger
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
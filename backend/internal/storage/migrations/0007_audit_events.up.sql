@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+	id BIGSERIAL PRIMARY KEY,
+	action VARCHAR(64) NOT NULL,
+	actor_address VARCHAR(42) NOT NULL,
+	api_key_id INTEGER,
+	request_id VARCHAR(128) NOT NULL,
+	ip VARCHAR(64) NOT NULL,
+	method VARCHAR(10) NOT NULL,
+	path VARCHAR(512) NOT NULL,
+	payload_hash VARCHAR(64) NOT NULL,
+	outcome VARCHAR(16) NOT NULL,
+	status_code INTEGER NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The audit log is append-only
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_no_update ON audit_events;
+CREATE TRIGGER audit_events_no_update
+	BEFORE UPDATE OR DELETE ON audit_events
+	FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate
+	BEFORE TRUNCATE ON audit_events
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_address);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events(action);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created ON audit_events(created_at);
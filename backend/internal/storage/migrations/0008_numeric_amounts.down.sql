@@ -0,0 +1,11 @@
+ALTER TABLE tokens
+	ALTER COLUMN total_supply TYPE VARCHAR(255) USING total_supply::TEXT;
+
+ALTER TABLE presales
+	ALTER COLUMN rate TYPE VARCHAR(255) USING rate::TEXT,
+	ALTER COLUMN soft_cap TYPE VARCHAR(255) USING soft_cap::TEXT,
+	ALTER COLUMN hard_cap TYPE VARCHAR(255) USING hard_cap::TEXT;
+
+ALTER TABLE presale_participations
+	ALTER COLUMN amount_eth TYPE VARCHAR(255) USING amount_eth::TEXT,
+	ALTER COLUMN amount_tokens TYPE VARCHAR(255) USING amount_tokens::TEXT;
@@ -0,0 +1,13 @@
+-- Token amounts are uint256 values in wei; NUMERIC(78,0) holds any uint256
+-- and lets Postgres sum and sort them.
+ALTER TABLE tokens
+	ALTER COLUMN total_supply TYPE NUMERIC(78,0) USING total_supply::NUMERIC(78,0);
+
+ALTER TABLE presales
+	ALTER COLUMN rate TYPE NUMERIC(78,0) USING rate::NUMERIC(78,0),
+	ALTER COLUMN soft_cap TYPE NUMERIC(78,0) USING soft_cap::NUMERIC(78,0),
+	ALTER COLUMN hard_cap TYPE NUMERIC(78,0) USING hard_cap::NUMERIC(78,0);
+
+ALTER TABLE presale_participations
+	ALTER COLUMN amount_eth TYPE NUMERIC(78,0) USING amount_eth::NUMERIC(78,0),
+	ALTER COLUMN amount_tokens TYPE NUMERIC(78,0) USING amount_tokens::NUMERIC(78,0);
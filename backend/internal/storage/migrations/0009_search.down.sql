@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_presales_creator_lower;
+DROP INDEX IF EXISTS idx_presales_address_lower;
+DROP INDEX IF EXISTS idx_tokens_creator_lower;
+DROP INDEX IF EXISTS idx_tokens_address_lower;
+DROP INDEX IF EXISTS idx_tokens_symbol_trgm;
+DROP INDEX IF EXISTS idx_tokens_name_trgm;
+DROP INDEX IF EXISTS idx_tokens_search;
+
+ALTER TABLE tokens DROP COLUMN IF EXISTS search_vector;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,19 @@
+-- Full-text and fuzzy search over tokens. Symbols weigh more than names;
+-- the 'simple' configuration keeps tickers and names unstemmed.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE tokens
+	ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', symbol), 'A') ||
+		setweight(to_tsvector('simple', name), 'B')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tokens_search ON tokens USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_tokens_name_trgm ON tokens USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tokens_symbol_trgm ON tokens USING GIN (symbol gin_trgm_ops);
+
+-- Addresses are matched case-insensitively
+CREATE INDEX IF NOT EXISTS idx_tokens_address_lower ON tokens (LOWER(address));
+CREATE INDEX IF NOT EXISTS idx_tokens_creator_lower ON tokens (LOWER(creator_address));
+CREATE INDEX IF NOT EXISTS idx_presales_address_lower ON presales (LOWER(address));
+CREATE INDEX IF NOT EXISTS idx_presales_creator_lower ON presales (LOWER(creator_address));
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS presale_status_history;
+
+DROP INDEX IF EXISTS idx_presales_status;
+
+ALTER TABLE presales
+	ADD COLUMN IF NOT EXISTS active BOOLEAN DEFAULT true,
+	ADD COLUMN IF NOT EXISTS finalized BOOLEAN DEFAULT false;
+
+UPDATE presales
+SET active = status IN ('live', 'scheduled', 'unfunded', 'draft'),
+	finalized = status IN ('finalized_success', 'finalized_failed');
+
+ALTER TABLE presales
+	DROP CONSTRAINT IF EXISTS presales_status_check,
+	DROP COLUMN IF EXISTS status,
+	DROP COLUMN IF EXISTS status_updated_at;
@@ -0,0 +1,45 @@
+-- Presale lifecycle. The stored status is the one set by the last transition;
+-- filled and ended are computed from the raise and the deadline when read.
+ALTER TABLE presales
+	ADD COLUMN IF NOT EXISTS status VARCHAR(32),
+	ADD COLUMN IF NOT EXISTS status_updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE presales p
+SET status = CASE
+		WHEN p.finalized AND (
+			SELECT COALESCE(SUM(amount_eth), 0) FROM presale_participations WHERE presale_id = p.id
+		) >= p.soft_cap THEN 'finalized_success'
+		WHEN p.finalized THEN 'finalized_failed'
+		WHEN p.active THEN 'live'
+		ELSE 'cancelled'
+	END,
+	status_updated_at = p.created_at
+WHERE p.status IS NULL;
+
+ALTER TABLE presales
+	ALTER COLUMN status SET NOT NULL,
+	ALTER COLUMN status_updated_at SET NOT NULL,
+	ADD CONSTRAINT presales_status_check CHECK (status IN (
+		'draft', 'unfunded', 'scheduled', 'live', 'cancelled', 'finalized_success', 'finalized_failed'
+	)),
+	DROP COLUMN IF EXISTS active,
+	DROP COLUMN IF EXISTS finalized;
+
+CREATE INDEX IF NOT EXISTS idx_presales_status ON presales(status);
+
+CREATE TABLE IF NOT EXISTS presale_status_history (
+	id BIGSERIAL PRIMARY KEY,
+	presale_id INTEGER NOT NULL REFERENCES presales(id),
+	from_status VARCHAR(32) NOT NULL,
+	to_status VARCHAR(32) NOT NULL,
+	actor_address VARCHAR(42) NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_presale_status_history_presale ON presale_status_history(presale_id, id);
+
+-- Existing presales start their history with the migrated status
+INSERT INTO presale_status_history (presale_id, from_status, to_status, actor_address, reason, created_at)
+SELECT id, '', status, creator_address, 'migrated', created_at
+FROM presales;
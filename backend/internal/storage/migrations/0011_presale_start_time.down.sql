@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_presales_start_time;
+
+ALTER TABLE presales DROP COLUMN IF EXISTS start_time;
@@ -0,0 +1,9 @@
+-- Presales open for contributions at start_time. Existing presales opened
+-- when they were created.
+ALTER TABLE presales ADD COLUMN IF NOT EXISTS start_time TIMESTAMP;
+
+UPDATE presales SET start_time = created_at WHERE start_time IS NULL;
+
+ALTER TABLE presales ALTER COLUMN start_time SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_presales_start_time ON presales(start_time);
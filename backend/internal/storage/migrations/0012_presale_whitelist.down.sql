@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS presale_whitelist_entries;
+
+ALTER TABLE presales
+	DROP COLUMN IF EXISTS merkle_root,
+	DROP COLUMN IF EXISTS whitelisted;
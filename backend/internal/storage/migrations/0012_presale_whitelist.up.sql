@@ -0,0 +1,16 @@
+-- Whitelisted presales accept contributions only from addresses on their
+-- whitelist. merkle_root is the root of the tree over the whitelist, set
+-- when the creator uploads it.
+ALTER TABLE presales
+	ADD COLUMN IF NOT EXISTS whitelisted BOOLEAN NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS merkle_root VARCHAR(66);
+
+-- Each entry keeps the proof of its leaf, so proofs are served without
+-- rebuilding the tree. An allocation of 0 means no per-address limit.
+CREATE TABLE IF NOT EXISTS presale_whitelist_entries (
+	presale_id INTEGER NOT NULL REFERENCES presales(id),
+	address VARCHAR(42) NOT NULL,
+	allocation NUMERIC(78,0) NOT NULL DEFAULT 0,
+	proof TEXT[] NOT NULL,
+	PRIMARY KEY (presale_id, address)
+);
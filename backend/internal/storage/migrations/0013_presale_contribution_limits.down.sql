@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_participations_presale_participant;
+
+ALTER TABLE presales
+	DROP COLUMN IF EXISTS max_contribution,
+	DROP COLUMN IF EXISTS min_contribution;
@@ -0,0 +1,7 @@
+-- Per-wallet contribution limits in wei. A wallet's total contribution must
+-- reach min_contribution and stay within max_contribution; 0 disables a limit.
+ALTER TABLE presales
+	ADD COLUMN IF NOT EXISTS min_contribution NUMERIC(78,0) NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS max_contribution NUMERIC(78,0) NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_participations_presale_participant ON presale_participations(presale_id, participant_address);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS presale_vesting_claims;
+
+ALTER TABLE presales
+	DROP COLUMN IF EXISTS vesting_duration_seconds,
+	DROP COLUMN IF EXISTS vesting_cliff_seconds,
+	DROP COLUMN IF EXISTS vesting_tge_bps,
+	DROP COLUMN IF EXISTS vesting;
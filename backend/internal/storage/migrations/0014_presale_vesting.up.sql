@@ -0,0 +1,21 @@
+-- Vesting presales hold bought tokens in the contract and release them from
+-- the successful finalization (TGE): vesting_tge_bps of each purchase at once,
+-- the rest linearly over vesting_duration_seconds after a cliff of
+-- vesting_cliff_seconds.
+ALTER TABLE presales
+	ADD COLUMN IF NOT EXISTS vesting BOOLEAN NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS vesting_tge_bps INTEGER NOT NULL DEFAULT 0 CHECK (vesting_tge_bps BETWEEN 0 AND 10000),
+	ADD COLUMN IF NOT EXISTS vesting_cliff_seconds BIGINT NOT NULL DEFAULT 0 CHECK (vesting_cliff_seconds >= 0),
+	ADD COLUMN IF NOT EXISTS vesting_duration_seconds BIGINT NOT NULL DEFAULT 0 CHECK (vesting_duration_seconds >= 0);
+
+-- Tokens claimed from vesting presales, in token base units
+CREATE TABLE IF NOT EXISTS presale_vesting_claims (
+	id SERIAL PRIMARY KEY,
+	presale_id INTEGER NOT NULL REFERENCES presales(id),
+	participant_address VARCHAR(42) NOT NULL,
+	amount_tokens NUMERIC(78,0) NOT NULL,
+	tx_hash VARCHAR(66) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vesting_claims_presale_participant ON presale_vesting_claims(presale_id, participant_address);
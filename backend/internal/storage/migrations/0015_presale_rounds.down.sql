@@ -0,0 +1,4 @@
+ALTER TABLE presale_participations
+	DROP COLUMN IF EXISTS round_index;
+
+DROP TABLE IF EXISTS presale_rounds;
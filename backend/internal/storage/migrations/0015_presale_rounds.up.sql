@@ -0,0 +1,18 @@
+-- Multi-round presales sell in consecutive rounds (seed, private, public...),
+-- each with its own rate, optional cap in wei (0 for up to the hard cap) and
+-- time window. Presales without rows here sell at presales.rate.
+CREATE TABLE IF NOT EXISTS presale_rounds (
+	presale_id INTEGER NOT NULL REFERENCES presales(id),
+	round_index INTEGER NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	rate NUMERIC(78,0) NOT NULL,
+	cap NUMERIC(78,0) NOT NULL DEFAULT 0,
+	start_time TIMESTAMP NOT NULL,
+	end_time TIMESTAMP NOT NULL,
+	PRIMARY KEY (presale_id, round_index),
+	CHECK (start_time < end_time)
+);
+
+-- The round a contribution was made in, NULL for presales without rounds
+ALTER TABLE presale_participations
+	ADD COLUMN IF NOT EXISTS round_index INTEGER;
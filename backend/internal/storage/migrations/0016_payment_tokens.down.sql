@@ -0,0 +1,7 @@
+ALTER TABLE presale_participations
+	RENAME COLUMN amount_paid TO amount_eth;
+
+ALTER TABLE presales
+	DROP COLUMN IF EXISTS payment_decimals,
+	DROP COLUMN IF EXISTS payment_symbol,
+	DROP COLUMN IF EXISTS payment_token;
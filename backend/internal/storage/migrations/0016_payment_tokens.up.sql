@@ -0,0 +1,10 @@
+-- Presales may be paid in an ERC-20 (ERC20PaymentPresale) instead of ETH.
+-- Caps, limits and contributions are in base units of the payment currency;
+-- ETH presales keep an empty payment_token.
+ALTER TABLE presales
+	ADD COLUMN IF NOT EXISTS payment_token VARCHAR(42) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS payment_symbol VARCHAR(20) NOT NULL DEFAULT 'ETH',
+	ADD COLUMN IF NOT EXISTS payment_decimals INTEGER NOT NULL DEFAULT 18 CHECK (payment_decimals BETWEEN 0 AND 36);
+
+ALTER TABLE presale_participations
+	RENAME COLUMN amount_eth TO amount_paid;
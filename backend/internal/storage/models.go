@@ -6,9 +6,19 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID        int       `json:"id" db:"id"`
-	Address   string    `json:"address" db:"address"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID          int       `json:"id" db:"id"`
+	AccountID   int       `json:"account_id" db:"account_id"`
+	Address     string    `json:"address" db:"address"`
+	Role        string    `json:"role" db:"role"`
+	DisplayName string    `json:"display_name" db:"display_name"`
+	AvatarURL   string    `json:"avatar_url" db:"avatar_url"`
+	Bio         string    `json:"bio" db:"bio"`
+	Website     string    `json:"website" db:"website"`
+	Twitter     string    `json:"twitter" db:"twitter"`
+	Telegram    string    `json:"telegram" db:"telegram"`
+	Discord     string    `json:"discord" db:"discord"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // Token represents a deployed token
@@ -17,7 +27,7 @@ type Token struct {
 	Address        string    `json:"address" db:"address"`
 	Name           string    `json:"name" db:"name"`
 	Symbol         string    `json:"symbol" db:"symbol"`
-	TotalSupply    string    `json:"total_supply" db:"total_supply"`
+	TotalSupply    BigInt    `json:"total_supply" db:"total_supply"`
 	CreatorAddress string    `json:"creator_address" db:"creator_address"`
 	TxHash         string    `json:"tx_hash" db:"tx_hash"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
@@ -25,18 +35,43 @@ type Token struct {
 
 // Presale represents a token presale
 type Presale struct {
-	ID             int       `json:"id" db:"id"`
-	Address        string    `json:"address" db:"address"`
-	TokenAddress   string    `json:"token_address" db:"token_address"`
-	CreatorAddress string    `json:"creator_address" db:"creator_address"`
-	Rate           string    `json:"rate" db:"rate"`
-	SoftCap        string    `json:"soft_cap" db:"soft_cap"`
-	HardCap        string    `json:"hard_cap" db:"hard_cap"`
-	Deadline       time.Time `json:"deadline" db:"deadline"`
-	TxHash         string    `json:"tx_hash" db:"tx_hash"`
-	Active         bool      `json:"active" db:"active"`
-	Finalized      bool      `json:"finalized" db:"finalized"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	ID              int       `json:"id" db:"id"`
+	Address         string    `json:"address" db:"address"`
+	TokenAddress    string    `json:"token_address" db:"token_address"`
+	CreatorAddress  string    `json:"creator_address" db:"creator_address"`
+	Rate            BigInt    `json:"rate" db:"rate"`
+	SoftCap         BigInt    `json:"soft_cap" db:"soft_cap"`
+	HardCap         BigInt    `json:"hard_cap" db:"hard_cap"`
+	MinContribution BigInt    `json:"min_contribution" db:"min_contribution"` // per wallet, 0 for no limit
+	MaxContribution BigInt    `json:"max_contribution" db:"max_contribution"` // per wallet, 0 for no limit
+	StartTime       time.Time `json:"start_time" db:"start_time"`             // contributions open
+	Deadline        time.Time `json:"deadline" db:"deadline"`
+	TxHash          string    `json:"tx_hash" db:"tx_hash"`
+	Status          string    `json:"status" db:"status"` // computed when read, see PresaleStatus
+	Whitelisted     bool      `json:"whitelisted" db:"whitelisted"`
+	MerkleRoot      string    `json:"merkle_root,omitempty" db:"merkle_root"`                 // root of the whitelist, once uploaded
+	Vesting         bool      `json:"vesting" db:"vesting"`                                   // bought tokens are released on the schedule below
+	VestingTGEBps   int       `json:"vesting_tge_bps" db:"vesting_tge_bps"`                   // released at finalization, in basis points
+	VestingCliff    int64     `json:"vesting_cliff_seconds" db:"vesting_cliff_seconds"`       // after finalization, before linear release
+	VestingDuration int64     `json:"vesting_duration_seconds" db:"vesting_duration_seconds"` // of the linear release after the cliff
+	PaymentToken    string    `json:"payment_token,omitempty" db:"payment_token"`             // ERC-20 contributions are paid in, empty for ETH
+	PaymentSymbol   string    `json:"payment_symbol" db:"payment_symbol"`                     // "ETH" for ETH
+	PaymentDecimals int       `json:"payment_decimals" db:"payment_decimals"`                 // of caps, limits and contributions
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+
+	StatusUpdatedAt time.Time `json:"status_updated_at" db:"status_updated_at"`
+
+	// Aggregated from presale_participations when the presale is read
+	Raised       BigInt `json:"raised" db:"raised"`
+	Contributors int    `json:"contributors" db:"contributors"`
+	ProgressBps  int    `json:"progress_bps" db:"progress_bps"` // raised / hard_cap in basis points
+	Remaining    BigInt `json:"remaining" db:"remaining"`       // hard-cap capacity left
+
+	// Token is joined in by listings that ask for it
+	Token *Token `json:"token,omitempty" db:"-"`
+
+	// Rounds of a multi-round presale, filled in by single-presale reads
+	Rounds []*PresaleRound `json:"rounds,omitempty" db:"-"`
 }
 
 // PresaleParticipation represents a user's participation in a presale
@@ -44,8 +79,136 @@ type PresaleParticipation struct {
 	ID               int       `json:"id" db:"id"`
 	PresaleID        int       `json:"presale_id" db:"presale_id"`
 	ParticipantAddr  string    `json:"participant_address" db:"participant_address"`
-	AmountETH        string    `json:"amount_eth" db:"amount_eth"`
-	AmountTokens     string    `json:"amount_tokens" db:"amount_tokens"`
+	AmountPaid       BigInt    `json:"amount_paid" db:"amount_paid"` // base units of the payment currency
+	AmountTokens     BigInt    `json:"amount_tokens" db:"amount_tokens"`
 	TxHash           string    `json:"tx_hash" db:"tx_hash"`
+	RoundIndex       *int      `json:"round_index,omitempty" db:"round_index"` // nil for presales without rounds
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+
+	// Payment currency of the presale, read with the participation
+	PaymentToken    string `json:"payment_token,omitempty" db:"payment_token"`
+	PaymentSymbol   string `json:"payment_symbol" db:"payment_symbol"`
+	PaymentDecimals int    `json:"payment_decimals" db:"payment_decimals"`
+}
+
+// PresaleRound is a pricing round of a multi-round presale. Rounds follow
+// each other without overlapping, and contributions buy at the rate of the
+// round open at the time.
+type PresaleRound struct {
+	PresaleID int       `json:"presale_id" db:"presale_id"`
+	Index     int       `json:"index" db:"round_index"` // from 0, in time order
+	Name      string    `json:"name" db:"name"`
+	Rate      BigInt    `json:"rate" db:"rate"`
+	Cap       BigInt    `json:"cap" db:"cap"` // wei the round may raise, 0 for up to the hard cap
+	StartTime time.Time `json:"start_time" db:"start_time"`
+	EndTime   time.Time `json:"end_time" db:"end_time"`
+
+	// Computed from presale_participations when the round is read
+	Status       string `json:"status" db:"-"` // see RoundStatus
+	Raised       BigInt `json:"raised" db:"raised"`
+	Contributors int    `json:"contributors" db:"contributors"`
+	ProgressBps  int    `json:"progress_bps" db:"progress_bps"` // raised / cap in basis points, 0 without a cap
+}
+
+// PresaleStatusChange is an entry in a presale's status history
+type PresaleStatusChange struct {
+	ID           int64     `json:"id" db:"id"`
+	PresaleID    int       `json:"presale_id" db:"presale_id"`
+	FromStatus   string    `json:"from_status" db:"from_status"` // empty for the initial status
+	ToStatus     string    `json:"to_status" db:"to_status"`
+	ActorAddress string    `json:"actor_address" db:"actor_address"`
+	Reason       string    `json:"reason" db:"reason"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// WhitelistEntry is an address on a presale's whitelist with the proof of its
+// leaf under the presale's Merkle root
+type WhitelistEntry struct {
+	PresaleID  int      `json:"presale_id" db:"presale_id"`
+	Address    string   `json:"address" db:"address"`
+	Allocation BigInt   `json:"allocation" db:"allocation"` // wei the address may contribute in total, 0 for no limit
+	Proof      []string `json:"proof" db:"proof"`           // sibling hashes, bottom up
+}
+
+// VestingClaim records tokens claimed from a vesting presale
+type VestingClaim struct {
+	ID              int       `json:"id" db:"id"`
+	PresaleID       int       `json:"presale_id" db:"presale_id"`
+	ParticipantAddr string    `json:"participant_address" db:"participant_address"`
+	AmountTokens    BigInt    `json:"amount_tokens" db:"amount_tokens"` // token base units
+	TxHash          string    `json:"tx_hash" db:"tx_hash"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// VestingBalance sums the tokens an address bought from a presale and the
+// tokens it has claimed, in token base units
+type VestingBalance struct {
+	Address   string `json:"address"`
+	Purchased BigInt `json:"purchased"`
+	Claimed   BigInt `json:"claimed"`
+}
+
+// PortfolioPosition sums a participant's contributions to one presale
+type PortfolioPosition struct {
+	Presale            *Presale  `json:"presale"`
+	Contributed        BigInt    `json:"contributed"`
+	TokensReceived     BigInt    `json:"tokens_received"`
+	Contributions      int       `json:"contributions"`
+	FirstContributedAt time.Time `json:"first_contributed_at"`
+	LastContributedAt  time.Time `json:"last_contributed_at"`
+}
+
+// Contributor sums one address's contributions to a presale
+type Contributor struct {
+	Rank               int       `json:"rank"` // position by amount contributed
+	Address            string    `json:"address"`
+	Contributed        BigInt    `json:"contributed"`
+	TokensReceived     BigInt    `json:"tokens_received"`
+	Contributions      int       `json:"contributions"`
+	ShareBps           int       `json:"share_bps"` // share of the raise in basis points
+	FirstContributedAt time.Time `json:"first_contributed_at"`
+	LastContributedAt  time.Time `json:"last_contributed_at"`
+}
+
+// APIKey represents an API key issued to a user address. Only the SHA-256
+// hash of the secret is stored; the plaintext is returned once at creation.
+type APIKey struct {
+	ID           int        `json:"id" db:"id"`
+	OwnerAddress string     `json:"owner_address" db:"owner_address"`
+	Name         string     `json:"name" db:"name"`
+	Prefix       string     `json:"prefix" db:"key_prefix"`
+	KeyHash      string     `json:"-" db:"key_hash"`
+	Scopes       []string   `json:"scopes" db:"scopes"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt   *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Session represents a login session backing a JWT
+type Session struct {
+	ID           string     `json:"id" db:"id"`
+	Address      string     `json:"address" db:"address"`
+	UserAgent    string     `json:"user_agent" db:"user_agent"`
+	IP           string     `json:"ip" db:"ip"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	LastActiveAt time.Time  `json:"last_active_at" db:"last_active_at"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// AuditEvent represents an entry in the append-only audit log
+type AuditEvent struct {
+	ID           int64     `json:"id" db:"id"`
+	Action       string    `json:"action" db:"action"`
+	ActorAddress string    `json:"actor_address" db:"actor_address"`
+	APIKeyID     *int      `json:"api_key_id" db:"api_key_id"`
+	RequestID    string    `json:"request_id" db:"request_id"`
+	IP           string    `json:"ip" db:"ip"`
+	Method       string    `json:"method" db:"method"`
+	Path         string    `json:"path" db:"path"`
+	PayloadHash  string    `json:"payload_hash" db:"payload_hash"`
+	Outcome      string    `json:"outcome" db:"outcome"`
+	StatusCode   int       `json:"status_code" db:"status_code"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 }
\ No newline at end of file
@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Sort keys accepted by the list queries
+const (
+	SortCreatedAt   = "created_at"
+	SortDeadline    = "deadline"
+	SortStartTime   = "start_time"
+	SortHardCap     = "hard_cap"
+	SortTotalSupply = "total_supply"
+	SortProgress    = "progress"
+
+	SortContributed        = "contributed"
+	SortContributions      = "contributions"
+	SortFirstContributedAt = "first_contributed_at"
+	SortLastContributedAt  = "last_contributed_at"
+)
+
+// Sort orders
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// Cursor is the position after the last row of a page. Rows are ordered by
+// the sort column and then by ID, so the pair identifies a row uniquely.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    int    `json:"i"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor returned by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil || cursor.ID <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return cursor, nil
+}
+
+// PageQuery selects one page of a keyset-paginated list
+type PageQuery struct {
+	Sort  string
+	Order string
+	After *Cursor // nil for the first page
+	Limit int
+}
+
+// TokenQuery selects a page of tokens
+type TokenQuery struct {
+	PageQuery
+	CreatorAddresses []string
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+}
+
+// PresaleQuery selects a page of presales. A nil CreatorAddresses matches
+// presales of every creator.
+type PresaleQuery struct {
+	PageQuery
+	CreatorAddresses []string
+	TokenAddress     string
+	Statuses         []string // computed statuses, any of which matches
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+	DeadlineFrom     *time.Time
+	DeadlineTo       *time.Time
+	StatusFrom       *time.Time // status set by a transition at or after
+	MinProgressBps   int
+	IncludeToken     bool // fill in Presale.Token
+}
+
+// ContributorQuery selects the top contributors of a presale
+type ContributorQuery struct {
+	PresaleID int
+	Sort      string
+	Order     string
+	Limit     int
+}
+
+// sortKind is the type of a sort column, used to cast and compare cursor values
+type sortKind int
+
+const (
+	sortTime sortKind = iota
+	sortNumeric
+)
+
+// sortColumn maps a sort key to its SQL expression
+type sortColumn struct {
+	expr string
+	kind sortKind
+}
+
+var tokenSortColumns = map[string]sortColumn{
+	SortCreatedAt:   {expr: "created_at", kind: sortTime},
+	SortTotalSupply: {expr: "total_supply", kind: sortNumeric},
+}
+
+var presaleSortColumns = map[string]sortColumn{
+	SortCreatedAt: {expr: "p.created_at", kind: sortTime},
+	SortDeadline:  {expr: "p.deadline", kind: sortTime},
+	SortStartTime: {expr: "p.start_time", kind: sortTime},
+	SortHardCap:   {expr: "p.hard_cap", kind: sortNumeric},
+	SortProgress:  {expr: presaleProgressExpr, kind: sortNumeric},
+}
+
+var contributorSortColumns = map[string]string{
+	SortContributed:        "contributed",
+	SortContributions:      "contributions",
+	SortFirstContributedAt: "first_at",
+	SortLastContributedAt:  "last_at",
+}
+
+// sqlType is the type a cursor value is cast to in SQL
+func (k sortKind) sqlType() string {
+	if k == sortNumeric {
+		return "NUMERIC"
+	}
+	return "TIMESTAMP"
+}
+
+// formatTimeValue formats a time sort value for a cursor
+func formatTimeValue(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// compareSortValues compares two cursor values of a kind
+func compareSortValues(kind sortKind, a, b string) int {
+	if kind == sortNumeric {
+		x, _ := ParseBigInt(a)
+		y, _ := ParseBigInt(b)
+		return x.Cmp(y)
+	}
+
+	x, _ := time.Parse(time.RFC3339Nano, a)
+	y, _ := time.Parse(time.RFC3339Nano, b)
+	return x.Compare(y)
+}
+
+// validateCursorValue checks that a cursor value parses as its column kind
+func validateCursorValue(kind sortKind, value string) error {
+	if kind == sortNumeric {
+		if _, ok := ParseBigInt(value); !ok {
+			return fmt.Errorf("invalid cursor")
+		}
+		return nil
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+		return fmt.Errorf("invalid cursor")
+	}
+	return nil
+}
+
+// pageColumn validates the page query against the sortable columns of a
+// list and returns the column to order by
+func (q *PageQuery) pageColumn(columns map[string]sortColumn) (sortColumn, error) {
+	column, ok := columns[q.Sort]
+	if !ok {
+		return sortColumn{}, fmt.Errorf("invalid sort %q", q.Sort)
+	}
+
+	if q.Order != OrderAsc && q.Order != OrderDesc {
+		return sortColumn{}, fmt.Errorf("invalid order %q", q.Order)
+	}
+
+	if q.Limit <= 0 {
+		return sortColumn{}, fmt.Errorf("limit must be positive")
+	}
+
+	if q.After != nil {
+		if q.After.Sort != q.Sort || q.After.Order != q.Order {
+			return sortColumn{}, fmt.Errorf("cursor does not match sort and order")
+		}
+		if err := validateCursorValue(column.kind, q.After.Value); err != nil {
+			return sortColumn{}, err
+		}
+	}
+
+	return column, nil
+}
+
+// keysetClause returns the ORDER BY clause of a page and, after a cursor,
+// the condition selecting rows past it. Placeholders start at $argIndex.
+func (q *PageQuery) keysetClause(column sortColumn, idExpr string, argIndex int) (condition string, args []interface{}, orderBy string) {
+	direction, comparison := "DESC", "<"
+	if q.Order == OrderAsc {
+		direction, comparison = "ASC", ">"
+	}
+
+	orderBy = fmt.Sprintf("ORDER BY %s %s, %s %s", column.expr, direction, idExpr, direction)
+
+	if q.After != nil {
+		condition = fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d)",
+			column.expr, idExpr, comparison, argIndex, column.kind.sqlType(), argIndex+1)
+		args = []interface{}{q.After.Value, q.After.ID}
+	}
+
+	return condition, args, orderBy
+}
+
+// isAfter reports whether a row with the given sort value and ID comes after
+// the page cursor, for the in-memory repositories
+func (q *PageQuery) isAfter(kind sortKind, value string, id int) bool {
+	if q.After == nil {
+		return true
+	}
+	return q.less(kind, q.After.Value, q.After.ID, value, id)
+}
+
+// less reports whether row a sorts before row b in the page order
+func (q *PageQuery) less(kind sortKind, aValue string, aID int, bValue string, bID int) bool {
+	cmp := compareSortValues(kind, aValue, bValue)
+	if cmp == 0 {
+		cmp = aID - bID
+	}
+	if q.Order == OrderDesc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// next returns the cursor pointing past a row
+func (q *PageQuery) next(value string, id int) *Cursor {
+	return &Cursor{Sort: q.Sort, Order: q.Order, Value: value, ID: id}
+}
+
+// tokenSortValue returns the cursor value of a token for a sort key
+func tokenSortValue(token *Token, sort string) string {
+	if sort == SortTotalSupply {
+		return token.TotalSupply.String()
+	}
+	return formatTimeValue(token.CreatedAt)
+}
+
+// presaleSortValue returns the cursor value of a presale for a sort key
+func presaleSortValue(presale *Presale, sort string) string {
+	switch sort {
+	case SortDeadline:
+		return formatTimeValue(presale.Deadline)
+	case SortStartTime:
+		return formatTimeValue(presale.StartTime)
+	case SortHardCap:
+		return presale.HardCap.String()
+	case SortProgress:
+		return strconv.Itoa(presale.ProgressBps)
+	default:
+		return formatTimeValue(presale.CreatedAt)
+	}
+}
+
+// tokenPage trims rows fetched with one extra row to the page limit and
+// returns the cursor of the next page when the extra row was present
+func tokenPage(page *PageQuery, tokens []*Token) ([]*Token, *Cursor) {
+	if len(tokens) <= page.Limit {
+		return tokens, nil
+	}
+	tokens = tokens[:page.Limit]
+	last := tokens[len(tokens)-1]
+	return tokens, page.next(tokenSortValue(last, page.Sort), last.ID)
+}
+
+// presalePage trims rows fetched with one extra row to the page limit and
+// returns the cursor of the next page when the extra row was present
+func presalePage(page *PageQuery, presales []*Presale) ([]*Presale, *Cursor) {
+	if len(presales) <= page.Limit {
+		return presales, nil
+	}
+	presales = presales[:page.Limit]
+	last := presales[len(presales)-1]
+	return presales, page.next(presaleSortValue(last, page.Sort), last.ID)
+}
@@ -0,0 +1,1143 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Column lists shared by the queries and scan helpers below
+const (
+	tokenColumns = `id, address, name, symbol, total_supply, creator_address, tx_hash, created_at`
+
+	// presaleProgressExpr is raised / hard_cap in basis points
+	presaleProgressExpr = `COALESCE(LEAST(FLOOR(s.raised * 10000 / NULLIF(p.hard_cap, 0)), 2147483647), 0)::INTEGER`
+
+	// presaleStatusExpr is the SQL form of PresaleStatus
+	presaleStatusExpr = `CASE
+		       WHEN p.status NOT IN ('scheduled', 'live') THEN p.status
+		       WHEN p.start_time > NOW() THEN 'scheduled'
+		       WHEN s.raised >= p.hard_cap THEN 'filled'
+		       WHEN p.deadline <= NOW() THEN 'ended'
+		       ELSE 'live'
+		       END`
+
+	// presaleFields and presaleFrom read presales (aliased p) together with
+	// their contribution aggregates, computed by Postgres over the NUMERIC amounts
+	presaleFields = `p.id, p.address, p.token_address, p.creator_address, p.rate, p.soft_cap, p.hard_cap,
+		       p.min_contribution, p.max_contribution,
+		       p.start_time, p.deadline, p.tx_hash, ` + presaleStatusExpr + `, p.whitelisted, COALESCE(p.merkle_root, ''),
+		       p.vesting, p.vesting_tge_bps, p.vesting_cliff_seconds, p.vesting_duration_seconds,
+		       p.payment_token, p.payment_symbol, p.payment_decimals,
+		       p.created_at, p.status_updated_at,
+		       s.raised, s.contributors, ` + presaleProgressExpr + `,
+		       GREATEST(p.hard_cap - s.raised, 0)`
+
+	presaleFrom = `
+		FROM presales p
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(SUM(amount_paid), 0) AS raised,
+			       COUNT(DISTINCT participant_address) AS contributors
+			FROM presale_participations
+			WHERE presale_id = p.id
+		) s`
+
+	presaleSelect = `SELECT ` + presaleFields + presaleFrom
+
+	// presaleWithTokenSelect also joins the presale's token (aliased t)
+	presaleTokenFields = `
+		       t.id, t.address, t.name, t.symbol, t.total_supply, t.creator_address, t.tx_hash, t.created_at`
+
+	presaleTokenJoin = `
+		JOIN tokens t ON t.address = p.token_address`
+
+	presaleWithTokenSelect = `SELECT ` + presaleFields + `,` + presaleTokenFields + presaleFrom + presaleTokenJoin
+
+	// Search columns and conditions over a token aliased t. $1 is the raw
+	// term, $2 a prefix tsquery (possibly empty) and $3 the headline options.
+	searchQueryJoin = `
+		CROSS JOIN (SELECT to_tsquery('simple', NULLIF($2, '')) AS ts) q`
+
+	searchTokenRank = `COALESCE(ts_rank(t.search_vector, q.ts), 0) + GREATEST(similarity(t.symbol, $1), similarity(t.name, $1)) AS rank,
+		       COALESCE(ts_headline('simple', t.symbol || ' ' || t.name, q.ts, $3), t.symbol || ' ' || t.name) AS headline`
+
+	searchTokenMatch = `t.search_vector @@ q.ts OR t.symbol % $1 OR t.name % $1`
+
+	// participationColumns reads participations (aliased pp) with the payment
+	// currency of their presale (aliased p)
+	participationColumns = `pp.id, pp.presale_id, pp.participant_address, pp.amount_paid, pp.amount_tokens, pp.tx_hash,
+		       pp.round_index, pp.created_at, p.payment_token, p.payment_symbol, p.payment_decimals`
+
+	// contributedQuery sums the contributions of address $2 to presale $1
+	contributedQuery = `
+		SELECT COALESCE(SUM(amount_paid), 0)
+		FROM presale_participations
+		WHERE presale_id = $1 AND participant_address = $2`
+
+	// roundsQuery reads the rounds of presale $1 with their raise, in order
+	roundsQuery = `
+		SELECT r.presale_id, r.round_index, r.name, r.rate, r.cap, r.start_time, r.end_time,
+		       COALESCE(SUM(pp.amount_paid), 0), COUNT(DISTINCT pp.participant_address)
+		FROM presale_rounds r
+		LEFT JOIN presale_participations pp ON pp.presale_id = r.presale_id AND pp.round_index = r.round_index
+		WHERE r.presale_id = $1
+		GROUP BY r.presale_id, r.round_index
+		ORDER BY r.round_index`
+
+	// vestingBalanceQuery sums the tokens address $2 bought from presale $1
+	// and the tokens it claimed
+	vestingBalanceQuery = `
+		SELECT (SELECT COALESCE(SUM(amount_tokens), 0)
+		        FROM presale_participations
+		        WHERE presale_id = $1 AND participant_address = $2),
+		       (SELECT COALESCE(SUM(amount_tokens), 0)
+		        FROM presale_vesting_claims
+		        WHERE presale_id = $1 AND participant_address = $2)`
+)
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// NewPostgresRepositories creates repositories backed by PostgreSQL
+func NewPostgresRepositories(db *sql.DB) *Repositories {
+	return &Repositories{
+		Tokens:         &PostgresTokenRepository{db: db},
+		Presales:       &PostgresPresaleRepository{db: db},
+		Participations: &PostgresParticipationRepository{db: db},
+		Whitelists:     &PostgresWhitelistRepository{db: db},
+		Vesting:        &PostgresVestingRepository{db: db},
+	}
+}
+
+// PostgresTokenRepository is a TokenRepository backed by PostgreSQL
+type PostgresTokenRepository struct {
+	db *sql.DB
+}
+
+// Create stores a token
+func (r *PostgresTokenRepository) Create(token *Token) error {
+	query := `
+		INSERT INTO tokens (address, name, symbol, total_supply, creator_address, tx_hash)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		token.Address,
+		token.Name,
+		token.Symbol,
+		token.TotalSupply,
+		token.CreatorAddress,
+		token.TxHash,
+	).Scan(&token.ID, &token.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByAddress gets a token by contract address
+func (r *PostgresTokenRepository) GetByAddress(address string) (*Token, error) {
+	query := `SELECT ` + tokenColumns + ` FROM tokens WHERE address = $1`
+
+	token, err := scanToken(r.db.QueryRow(query, address))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Exists reports whether a token with the address is stored
+func (r *PostgresTokenRepository) Exists(address string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM tokens WHERE address = $1)`, address).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check token: %w", err)
+	}
+	return exists, nil
+}
+
+// List returns a page of tokens
+func (r *PostgresTokenRepository) List(query *TokenQuery) ([]*Token, *Cursor, error) {
+	column, err := query.pageColumn(tokenSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var filter sqlFilter
+	if query.CreatorAddresses != nil {
+		filter.add("creator_address = ANY($%d)", pq.Array(query.CreatorAddresses))
+	}
+	if query.CreatedFrom != nil {
+		filter.add("created_at >= $%d", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		filter.add("created_at < $%d", *query.CreatedTo)
+	}
+
+	orderBy := filter.addKeyset(&query.PageQuery, column, "id")
+	filter.args = append(filter.args, query.Limit+1)
+
+	sqlQuery := fmt.Sprintf(`SELECT %s FROM tokens %s %s LIMIT $%d`,
+		tokenColumns, filter.where(), orderBy, len(filter.args))
+
+	rows, err := r.db.Query(sqlQuery, filter.args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*Token
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	tokens, next := tokenPage(&query.PageQuery, tokens)
+	return tokens, next, nil
+}
+
+// Search finds tokens by name, symbol, address or creator, exact address
+// matches first, then by full-text rank plus trigram similarity
+func (r *PostgresTokenRepository) Search(term string, limit int) ([]*TokenSearchResult, error) {
+	query := `
+		SELECT ` + tokenColumns + `,
+		       LOWER(address) = LOWER($1) OR LOWER(creator_address) = LOWER($1) AS exact_match,
+		       ` + searchTokenRank + `
+		FROM tokens t` + searchQueryJoin + `
+		WHERE LOWER(address) = LOWER($1) OR LOWER(creator_address) = LOWER($1) OR ` + searchTokenMatch + `
+		ORDER BY LOWER(address) = LOWER($1) DESC, exact_match DESC, rank DESC, id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(query, term, searchTSQuery(term), headlineOptions, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*TokenSearchResult
+	for rows.Next() {
+		result := &TokenSearchResult{Token: &Token{}}
+		dest := append(tokenDest(result.Token), &result.ExactMatch, &result.Rank, &result.Headline)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan token: %w", err)
+		}
+		result.Headline = finishHeadline(result.Headline)
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search tokens: %w", err)
+	}
+
+	return results, nil
+}
+
+// PostgresPresaleRepository is a PresaleRepository backed by PostgreSQL
+type PostgresPresaleRepository struct {
+	db *sql.DB
+}
+
+// Create stores a presale and starts its status history
+func (r *PostgresPresaleRepository) Create(presale *Presale) error {
+	query := `
+		WITH inserted AS (
+			INSERT INTO presales (address, token_address, creator_address, rate, soft_cap, hard_cap,
+			                      min_contribution, max_contribution, start_time, deadline, tx_hash, status, whitelisted,
+			                      vesting, vesting_tge_bps, vesting_cliff_seconds, vesting_duration_seconds,
+			                      payment_token, payment_symbol, payment_decimals)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			RETURNING id, creator_address, status, created_at, status_updated_at
+		), history AS (
+			INSERT INTO presale_status_history (presale_id, from_status, to_status, actor_address, reason)
+			SELECT id, '', status, creator_address, 'created' FROM inserted
+		)
+		SELECT id, created_at, status_updated_at FROM inserted
+	`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		query,
+		presale.Address,
+		presale.TokenAddress,
+		presale.CreatorAddress,
+		presale.Rate,
+		presale.SoftCap,
+		presale.HardCap,
+		presale.MinContribution,
+		presale.MaxContribution,
+		presale.StartTime,
+		presale.Deadline,
+		presale.TxHash,
+		presale.Status,
+		presale.Whitelisted,
+		presale.Vesting,
+		presale.VestingTGEBps,
+		presale.VestingCliff,
+		presale.VestingDuration,
+		presale.PaymentToken,
+		presale.PaymentSymbol,
+		presale.PaymentDecimals,
+	).Scan(&presale.ID, &presale.CreatedAt, &presale.StatusUpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert presale: %w", err)
+	}
+
+	if len(presale.Rounds) > 0 {
+		stmt, err := tx.Prepare(`
+			INSERT INTO presale_rounds (presale_id, round_index, name, rate, cap, start_time, end_time)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare round insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, round := range presale.Rounds {
+			round.PresaleID = presale.ID
+			if _, err := stmt.Exec(presale.ID, round.Index, round.Name, round.Rate, round.Cap, round.StartTime, round.EndTime); err != nil {
+				return fmt.Errorf("failed to insert round: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit presale: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID gets a presale by ID
+func (r *PostgresPresaleRepository) GetByID(id int) (*Presale, error) {
+	query := presaleSelect + ` WHERE p.id = $1`
+
+	presale, err := scanPresale(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get presale: %w", err)
+	}
+
+	if presale.Rounds, err = queryRounds(r.db, id); err != nil {
+		return nil, err
+	}
+
+	return presale, nil
+}
+
+// List returns a page of presales
+func (r *PostgresPresaleRepository) List(query *PresaleQuery) ([]*Presale, *Cursor, error) {
+	column, err := query.pageColumn(presaleSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var filter sqlFilter
+	if query.CreatorAddresses != nil {
+		filter.add("p.creator_address = ANY($%d)", pq.Array(query.CreatorAddresses))
+	}
+	if query.TokenAddress != "" {
+		filter.add("p.token_address = $%d", query.TokenAddress)
+	}
+	if len(query.Statuses) > 0 {
+		for _, status := range query.Statuses {
+			if !IsPresaleStatus(status) {
+				return nil, nil, fmt.Errorf("invalid status %q", status)
+			}
+		}
+		filter.add(presaleStatusExpr+" = ANY($%d)", pq.Array(query.Statuses))
+	}
+	if query.CreatedFrom != nil {
+		filter.add("p.created_at >= $%d", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		filter.add("p.created_at < $%d", *query.CreatedTo)
+	}
+	if query.DeadlineFrom != nil {
+		filter.add("p.deadline >= $%d", *query.DeadlineFrom)
+	}
+	if query.DeadlineTo != nil {
+		filter.add("p.deadline < $%d", *query.DeadlineTo)
+	}
+	if query.StatusFrom != nil {
+		filter.add("p.status_updated_at >= $%d", *query.StatusFrom)
+	}
+	if query.MinProgressBps > 0 {
+		filter.add(presaleProgressExpr+" >= $%d", query.MinProgressBps)
+	}
+
+	orderBy := filter.addKeyset(&query.PageQuery, column, "p.id")
+	filter.args = append(filter.args, query.Limit+1)
+
+	selectClause, scan := presaleSelect, scanPresale
+	if query.IncludeToken {
+		selectClause, scan = presaleWithTokenSelect, scanPresaleWithToken
+	}
+
+	sqlQuery := fmt.Sprintf(`%s %s %s LIMIT $%d`, selectClause, filter.where(), orderBy, len(filter.args))
+
+	rows, err := r.db.Query(sqlQuery, filter.args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list presales: %w", err)
+	}
+	defer rows.Close()
+
+	var presales []*Presale
+	for rows.Next() {
+		presale, err := scan(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan presale: %w", err)
+		}
+		presales = append(presales, presale)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list presales: %w", err)
+	}
+
+	presales, next := presalePage(&query.PageQuery, presales)
+	return presales, next, nil
+}
+
+// Search finds presales by presale, token or creator address and by their
+// token's name and symbol, exact address matches first. Drafts are not public
+// and never match.
+func (r *PostgresPresaleRepository) Search(term string, limit int) ([]*PresaleSearchResult, error) {
+	query := `
+		SELECT ` + presaleFields + `,` + presaleTokenFields + `,
+		       LOWER(p.address) = LOWER($1) OR LOWER(t.address) = LOWER($1) OR LOWER(p.creator_address) = LOWER($1) AS exact_match,
+		       ` + searchTokenRank + presaleFrom + presaleTokenJoin + searchQueryJoin + `
+		WHERE p.status <> 'draft'
+		  AND (LOWER(p.address) = LOWER($1) OR LOWER(t.address) = LOWER($1) OR LOWER(p.creator_address) = LOWER($1)
+		       OR ` + searchTokenMatch + `)
+		ORDER BY LOWER(p.address) = LOWER($1) OR LOWER(t.address) = LOWER($1) DESC, exact_match DESC, rank DESC, p.id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(query, term, searchTSQuery(term), headlineOptions, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search presales: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*PresaleSearchResult
+	for rows.Next() {
+		result := &PresaleSearchResult{Presale: &Presale{Token: &Token{}}}
+		dest := append(presaleDest(result.Presale), tokenDest(result.Presale.Token)...)
+		dest = append(dest, &result.ExactMatch, &result.Rank, &result.Headline)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan presale: %w", err)
+		}
+		result.Headline = finishHeadline(result.Headline)
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search presales: %w", err)
+	}
+
+	return results, nil
+}
+
+// Transition changes a presale's status under a row lock
+func (r *PostgresPresaleRepository) Transition(change *PresaleStatusChange, check TransitionFunc) (*Presale, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM presales WHERE id = $1 FOR UPDATE`, change.PresaleID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to lock presale: %w", err)
+	}
+
+	presale, err := scanPresale(tx.QueryRow(presaleSelect+` WHERE p.id = $1`, change.PresaleID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presale: %w", err)
+	}
+
+	if err := check(presale); err != nil {
+		return nil, err
+	}
+	change.FromStatus = presale.Status
+
+	err = tx.QueryRow(`
+		UPDATE presales SET status = $2, status_updated_at = NOW() WHERE id = $1
+		RETURNING status_updated_at
+	`, change.PresaleID, change.ToStatus).Scan(&presale.StatusUpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update presale status: %w", err)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO presale_status_history (presale_id, from_status, to_status, actor_address, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`,
+		change.PresaleID,
+		change.FromStatus,
+		change.ToStatus,
+		change.ActorAddress,
+		change.Reason,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert status change: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit status change: %w", err)
+	}
+
+	presale.Status = PresaleStatus(change.ToStatus, presale.Raised, presale.HardCap, presale.StartTime, presale.Deadline, time.Now())
+	return presale, nil
+}
+
+// StatusHistory lists the status changes of a presale, oldest first
+func (r *PostgresPresaleRepository) StatusHistory(presaleID int) ([]*PresaleStatusChange, error) {
+	rows, err := r.db.Query(`
+		SELECT id, presale_id, from_status, to_status, actor_address, reason, created_at
+		FROM presale_status_history
+		WHERE presale_id = $1
+		ORDER BY id
+	`, presaleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*PresaleStatusChange
+	for rows.Next() {
+		change := &PresaleStatusChange{}
+		err := rows.Scan(
+			&change.ID,
+			&change.PresaleID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.ActorAddress,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+
+	return changes, nil
+}
+
+// PostgresParticipationRepository is a ParticipationRepository backed by PostgreSQL
+type PostgresParticipationRepository struct {
+	db *sql.DB
+}
+
+// Reserve stores a participation under a row lock on its presale
+func (r *PostgresParticipationRepository) Reserve(presaleID int, participantAddress string, reserve ReserveFunc) (*PresaleParticipation, BigInt, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, BigInt{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Contributions to the same presale queue up on this lock, and each one
+	// then sums the participations committed before it
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM presales WHERE id = $1 FOR UPDATE`, presaleID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, BigInt{}, ErrNotFound
+		}
+		return nil, BigInt{}, fmt.Errorf("failed to lock presale: %w", err)
+	}
+
+	presale, err := scanPresale(tx.QueryRow(presaleSelect+` WHERE p.id = $1`, presaleID))
+	if err != nil {
+		return nil, BigInt{}, fmt.Errorf("failed to get presale: %w", err)
+	}
+
+	if presale.Rounds, err = queryRounds(tx, presaleID); err != nil {
+		return nil, BigInt{}, err
+	}
+
+	var contributed BigInt
+	err = tx.QueryRow(contributedQuery, presaleID, participantAddress).Scan(&contributed)
+	if err != nil {
+		return nil, BigInt{}, fmt.Errorf("failed to sum contributions: %w", err)
+	}
+
+	remaining := presale.Remaining.Big()
+	participation, err := reserve(presale, new(big.Int).Set(remaining), contributed.Big())
+	if err != nil {
+		return nil, BigInt{}, err
+	}
+	participation.PresaleID = presaleID
+	participation.ParticipantAddr = participantAddress
+	participation.PaymentToken = presale.PaymentToken
+	participation.PaymentSymbol = presale.PaymentSymbol
+	participation.PaymentDecimals = presale.PaymentDecimals
+
+	err = tx.QueryRow(`
+		INSERT INTO presale_participations (presale_id, participant_address, amount_paid, amount_tokens, tx_hash, round_index)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`,
+		participation.PresaleID,
+		participation.ParticipantAddr,
+		participation.AmountPaid,
+		participation.AmountTokens,
+		participation.TxHash,
+		participation.RoundIndex,
+	).Scan(&participation.ID, &participation.CreatedAt)
+	if err != nil {
+		return nil, BigInt{}, fmt.Errorf("failed to insert participation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, BigInt{}, fmt.Errorf("failed to commit participation: %w", err)
+	}
+
+	return participation, NewBigInt(remaining.Sub(remaining, participation.AmountPaid.Big())), nil
+}
+
+// Contributed sums the contributions of an address to a presale
+func (r *PostgresParticipationRepository) Contributed(presaleID int, participantAddress string) (BigInt, error) {
+	var contributed BigInt
+	if err := r.db.QueryRow(contributedQuery, presaleID, participantAddress).Scan(&contributed); err != nil {
+		return BigInt{}, fmt.Errorf("failed to sum contributions: %w", err)
+	}
+	return contributed, nil
+}
+
+// ListByParticipants lists participations made by any of the addresses
+func (r *PostgresParticipationRepository) ListByParticipants(participantAddresses []string) ([]*PresaleParticipation, error) {
+	query := `
+		SELECT ` + participationColumns + `
+		FROM presale_participations pp
+		JOIN presales p ON p.id = pp.presale_id
+		WHERE pp.participant_address = ANY($1)
+		ORDER BY pp.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, pq.Array(participantAddresses))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list participations: %w", err)
+	}
+	defer rows.Close()
+
+	var participations []*PresaleParticipation
+	for rows.Next() {
+		participation, err := scanParticipation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan participation: %w", err)
+		}
+		participations = append(participations, participation)
+	}
+
+	return participations, nil
+}
+
+// Portfolio sums the participations of the addresses per presale
+func (r *PostgresParticipationRepository) Portfolio(participantAddresses []string) ([]*PortfolioPosition, error) {
+	query := `
+		SELECT ` + presaleFields + `,` + presaleTokenFields + `,
+		       c.contributed, c.tokens_received, c.contributions, c.first_at, c.last_at` +
+		presaleFrom + presaleTokenJoin + `
+		JOIN (
+			SELECT presale_id,
+			       SUM(amount_paid) AS contributed,
+			       SUM(amount_tokens) AS tokens_received,
+			       COUNT(*) AS contributions,
+			       MIN(created_at) AS first_at,
+			       MAX(created_at) AS last_at
+			FROM presale_participations
+			WHERE participant_address = ANY($1)
+			GROUP BY presale_id
+		) c ON c.presale_id = p.id
+		ORDER BY c.last_at DESC, p.id DESC
+	`
+
+	rows, err := r.db.Query(query, pq.Array(participantAddresses))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []*PortfolioPosition
+	for rows.Next() {
+		position := &PortfolioPosition{Presale: &Presale{Token: &Token{}}}
+		dest := append(presaleDest(position.Presale), tokenDest(position.Presale.Token)...)
+		dest = append(dest,
+			&position.Contributed,
+			&position.TokensReceived,
+			&position.Contributions,
+			&position.FirstContributedAt,
+			&position.LastContributedAt,
+		)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan portfolio position: %w", err)
+		}
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	return positions, nil
+}
+
+// Contributors sums the participations of a presale per address. Ranks and
+// shares are computed over every contributor, before the limit applies.
+func (r *PostgresParticipationRepository) Contributors(query *ContributorQuery) ([]*Contributor, error) {
+	column, ok := contributorSortColumns[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort %q", query.Sort)
+	}
+
+	direction := "DESC"
+	switch query.Order {
+	case OrderAsc:
+		direction = "ASC"
+	case OrderDesc:
+	default:
+		return nil, fmt.Errorf("invalid order %q", query.Order)
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT RANK() OVER (ORDER BY SUM(amount_paid) DESC)::INTEGER AS rank,
+		       participant_address,
+		       SUM(amount_paid) AS contributed,
+		       SUM(amount_tokens),
+		       COUNT(*)::INTEGER AS contributions,
+		       COALESCE(FLOOR(SUM(amount_paid) * 10000 / NULLIF(SUM(SUM(amount_paid)) OVER (), 0)), 0)::INTEGER,
+		       MIN(created_at) AS first_at,
+		       MAX(created_at) AS last_at
+		FROM presale_participations
+		WHERE presale_id = $1
+		GROUP BY participant_address
+		ORDER BY %s %s, rank, participant_address
+		LIMIT $2
+	`, column, direction)
+
+	rows, err := r.db.Query(sqlQuery, query.PresaleID, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contributors: %w", err)
+	}
+	defer rows.Close()
+
+	var contributors []*Contributor
+	for rows.Next() {
+		contributor := &Contributor{}
+		err := rows.Scan(
+			&contributor.Rank,
+			&contributor.Address,
+			&contributor.Contributed,
+			&contributor.TokensReceived,
+			&contributor.Contributions,
+			&contributor.ShareBps,
+			&contributor.FirstContributedAt,
+			&contributor.LastContributedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contributor: %w", err)
+		}
+		contributors = append(contributors, contributor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list contributors: %w", err)
+	}
+
+	return contributors, nil
+}
+
+// PostgresWhitelistRepository is a WhitelistRepository backed by PostgreSQL
+type PostgresWhitelistRepository struct {
+	db *sql.DB
+}
+
+// Replace stores the whitelist of a presale under a row lock
+func (r *PostgresWhitelistRepository) Replace(presaleID int, root string, entries []*WhitelistEntry, check TransitionFunc) (*Presale, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM presales WHERE id = $1 FOR UPDATE`, presaleID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to lock presale: %w", err)
+	}
+
+	presale, err := scanPresale(tx.QueryRow(presaleSelect+` WHERE p.id = $1`, presaleID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presale: %w", err)
+	}
+
+	if err := check(presale); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM presale_whitelist_entries WHERE presale_id = $1`, presaleID); err != nil {
+		return nil, fmt.Errorf("failed to delete whitelist: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO presale_whitelist_entries (presale_id, address, allocation, proof)
+		VALUES ($1, $2, $3, $4)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare whitelist insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		entry.PresaleID = presaleID
+		if _, err := stmt.Exec(presaleID, entry.Address, entry.Allocation, pq.Array(entry.Proof)); err != nil {
+			return nil, fmt.Errorf("failed to insert whitelist entry: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE presales SET merkle_root = $2 WHERE id = $1`, presaleID, root); err != nil {
+		return nil, fmt.Errorf("failed to update merkle root: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit whitelist: %w", err)
+	}
+
+	presale.MerkleRoot = root
+	return presale, nil
+}
+
+// Entry gets the whitelist entry of an address
+func (r *PostgresWhitelistRepository) Entry(presaleID int, address string) (*WhitelistEntry, error) {
+	entry := &WhitelistEntry{}
+	err := r.db.QueryRow(`
+		SELECT presale_id, address, allocation, proof
+		FROM presale_whitelist_entries
+		WHERE presale_id = $1 AND address = $2
+	`, presaleID, address).Scan(&entry.PresaleID, &entry.Address, &entry.Allocation, pq.Array(&entry.Proof))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get whitelist entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// PostgresVestingRepository is a VestingRepository backed by PostgreSQL
+type PostgresVestingRepository struct {
+	db *sql.DB
+}
+
+// Balances sums the tokens bought from a presale and claimed per address
+func (r *PostgresVestingRepository) Balances(presaleID int) ([]*VestingBalance, error) {
+	rows, err := r.db.Query(`
+		SELECT b.participant_address, b.purchased, COALESCE(c.claimed, 0)
+		FROM (
+			SELECT participant_address, SUM(amount_tokens) AS purchased
+			FROM presale_participations
+			WHERE presale_id = $1
+			GROUP BY participant_address
+		) b
+		LEFT JOIN (
+			SELECT participant_address, SUM(amount_tokens) AS claimed
+			FROM presale_vesting_claims
+			WHERE presale_id = $1
+			GROUP BY participant_address
+		) c USING (participant_address)
+		ORDER BY b.purchased DESC, b.participant_address
+	`, presaleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vesting balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []*VestingBalance
+	for rows.Next() {
+		balance := &VestingBalance{}
+		if err := rows.Scan(&balance.Address, &balance.Purchased, &balance.Claimed); err != nil {
+			return nil, fmt.Errorf("failed to scan vesting balance: %w", err)
+		}
+		balances = append(balances, balance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list vesting balances: %w", err)
+	}
+
+	return balances, nil
+}
+
+// Balance sums the tokens an address bought from a presale and claimed
+func (r *PostgresVestingRepository) Balance(presaleID int, address string) (*VestingBalance, error) {
+	balance := &VestingBalance{Address: address}
+	if err := r.db.QueryRow(vestingBalanceQuery, presaleID, address).Scan(&balance.Purchased, &balance.Claimed); err != nil {
+		return nil, fmt.Errorf("failed to sum vesting balance: %w", err)
+	}
+	return balance, nil
+}
+
+// Claim stores a claim under a row lock on its presale
+func (r *PostgresVestingRepository) Claim(presaleID int, participantAddress string, claim ClaimFunc) (*VestingClaim, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM presales WHERE id = $1 FOR UPDATE`, presaleID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to lock presale: %w", err)
+	}
+
+	presale, err := scanPresale(tx.QueryRow(presaleSelect+` WHERE p.id = $1`, presaleID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presale: %w", err)
+	}
+
+	balance := &VestingBalance{Address: participantAddress}
+	err = tx.QueryRow(vestingBalanceQuery, presaleID, participantAddress).Scan(&balance.Purchased, &balance.Claimed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum vesting balance: %w", err)
+	}
+
+	stored, err := claim(presale, balance)
+	if err != nil {
+		return nil, err
+	}
+	stored.PresaleID = presaleID
+	stored.ParticipantAddr = participantAddress
+
+	err = tx.QueryRow(`
+		INSERT INTO presale_vesting_claims (presale_id, participant_address, amount_tokens, tx_hash)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`,
+		stored.PresaleID,
+		stored.ParticipantAddr,
+		stored.AmountTokens,
+		stored.TxHash,
+	).Scan(&stored.ID, &stored.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert vesting claim: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit vesting claim: %w", err)
+	}
+
+	return stored, nil
+}
+
+// sqlFilter accumulates the WHERE conditions and arguments of a list query
+type sqlFilter struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add appends a condition whose single placeholder is written as $%d
+func (f *sqlFilter) add(condition string, arg interface{}) {
+	f.args = append(f.args, arg)
+	f.conditions = append(f.conditions, fmt.Sprintf(condition, len(f.args)))
+}
+
+// addKeyset appends the cursor condition of a page and returns its ORDER BY clause
+func (f *sqlFilter) addKeyset(page *PageQuery, column sortColumn, idExpr string) string {
+	condition, args, orderBy := page.keysetClause(column, idExpr, len(f.args)+1)
+	if condition != "" {
+		f.args = append(f.args, args...)
+		f.conditions = append(f.conditions, condition)
+	}
+	return orderBy
+}
+
+// where returns the WHERE clause, or an empty string without conditions
+func (f *sqlFilter) where() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(f.conditions, " AND ")
+}
+
+// tokenDest returns the scan destinations of tokenColumns
+func tokenDest(token *Token) []interface{} {
+	return []interface{}{
+		&token.ID,
+		&token.Address,
+		&token.Name,
+		&token.Symbol,
+		&token.TotalSupply,
+		&token.CreatorAddress,
+		&token.TxHash,
+		&token.CreatedAt,
+	}
+}
+
+// presaleDest returns the scan destinations of presaleFields
+func presaleDest(presale *Presale) []interface{} {
+	return []interface{}{
+		&presale.ID,
+		&presale.Address,
+		&presale.TokenAddress,
+		&presale.CreatorAddress,
+		&presale.Rate,
+		&presale.SoftCap,
+		&presale.HardCap,
+		&presale.MinContribution,
+		&presale.MaxContribution,
+		&presale.StartTime,
+		&presale.Deadline,
+		&presale.TxHash,
+		&presale.Status,
+		&presale.Whitelisted,
+		&presale.MerkleRoot,
+		&presale.Vesting,
+		&presale.VestingTGEBps,
+		&presale.VestingCliff,
+		&presale.VestingDuration,
+		&presale.PaymentToken,
+		&presale.PaymentSymbol,
+		&presale.PaymentDecimals,
+		&presale.CreatedAt,
+		&presale.StatusUpdatedAt,
+		&presale.Raised,
+		&presale.Contributors,
+		&presale.ProgressBps,
+		&presale.Remaining,
+	}
+}
+
+// scanToken scans a row selected with tokenColumns
+func scanToken(row rowScanner) (*Token, error) {
+	token := &Token{}
+	if err := row.Scan(tokenDest(token)...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// scanPresale scans a row selected with presaleSelect
+func scanPresale(row rowScanner) (*Presale, error) {
+	presale := &Presale{}
+	if err := row.Scan(presaleDest(presale)...); err != nil {
+		return nil, err
+	}
+	return presale, nil
+}
+
+// scanPresaleWithToken scans a row selected with presaleWithTokenSelect
+func scanPresaleWithToken(row rowScanner) (*Presale, error) {
+	presale := &Presale{Token: &Token{}}
+	if err := row.Scan(append(presaleDest(presale), tokenDest(presale.Token)...)...); err != nil {
+		return nil, err
+	}
+	return presale, nil
+}
+
+// queryRounds reads the rounds of a presale with their raise, nil for a
+// presale without rounds
+func queryRounds(q queryer, presaleID int) ([]*PresaleRound, error) {
+	rows, err := q.Query(roundsQuery, presaleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presale rounds: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var rounds []*PresaleRound
+	for rows.Next() {
+		round := &PresaleRound{}
+		var raised BigInt
+		var contributors int
+		err := rows.Scan(&round.PresaleID, &round.Index, &round.Name, &round.Rate, &round.Cap,
+			&round.StartTime, &round.EndTime, &raised, &contributors)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan presale round: %w", err)
+		}
+		setRoundStats(round, raised.Big(), contributors, now)
+		rounds = append(rounds, round)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get presale rounds: %w", err)
+	}
+
+	return rounds, nil
+}
+
+// scanParticipation scans a row selected with participationColumns
+func scanParticipation(row rowScanner) (*PresaleParticipation, error) {
+	participation := &PresaleParticipation{}
+	err := row.Scan(
+		&participation.ID,
+		&participation.PresaleID,
+		&participation.ParticipantAddr,
+		&participation.AmountPaid,
+		&participation.AmountTokens,
+		&participation.TxHash,
+		&participation.RoundIndex,
+		&participation.CreatedAt,
+		&participation.PaymentToken,
+		&participation.PaymentSymbol,
+		&participation.PaymentDecimals,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return participation, nil
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"time"
+)
+
+// ErrNotFound is returned by repositories when a record does not exist
+var ErrNotFound = errors.New("not found")
+
+// Presale statuses. Draft, unfunded, scheduled, cancelled and the finalized
+// statuses are stored and changed by transitions; live, filled and ended are
+// computed when a presale is read. Presales migrated from before start times
+// may also have live stored.
+const (
+	PresaleStatusDraft            = "draft"
+	PresaleStatusUnfunded         = "unfunded"
+	PresaleStatusScheduled        = "scheduled"
+	PresaleStatusLive             = "live"
+	PresaleStatusFilled           = "filled"
+	PresaleStatusEnded            = "ended"
+	PresaleStatusFinalizedSuccess = "finalized_success"
+	PresaleStatusFinalizedFailed  = "finalized_failed"
+	PresaleStatusCancelled        = "cancelled"
+)
+
+// PresaleStatuses lists every presale status in lifecycle order
+var PresaleStatuses = []string{
+	PresaleStatusDraft,
+	PresaleStatusUnfunded,
+	PresaleStatusScheduled,
+	PresaleStatusLive,
+	PresaleStatusFilled,
+	PresaleStatusEnded,
+	PresaleStatusFinalizedSuccess,
+	PresaleStatusFinalizedFailed,
+	PresaleStatusCancelled,
+}
+
+// PresaleStatus computes the status of a presale from its stored status,
+// its raise and its schedule. A funded (scheduled) presale stays scheduled
+// until its start time and then is live, filled once the raise reaches the
+// hard cap, or ended after the deadline. presaleStatusExpr is the SQL form.
+func PresaleStatus(stored string, raised, hardCap BigInt, startTime, deadline, now time.Time) string {
+	if stored != PresaleStatusScheduled && stored != PresaleStatusLive {
+		return stored
+	}
+
+	switch {
+	case startTime.After(now):
+		return PresaleStatusScheduled
+	case raised.Cmp(hardCap) >= 0:
+		return PresaleStatusFilled
+	case !deadline.After(now):
+		return PresaleStatusEnded
+	default:
+		return PresaleStatusLive
+	}
+}
+
+// Round statuses, computed when a round is read
+const (
+	RoundStatusUpcoming = "upcoming"
+	RoundStatusActive   = "active"
+	RoundStatusFilled   = "filled"
+	RoundStatusEnded    = "ended"
+)
+
+// RoundStatus computes the status of a presale round from its window and
+// its raise. A round with a cap is filled once its raise reaches the cap.
+func RoundStatus(round *PresaleRound, now time.Time) string {
+	switch {
+	case round.StartTime.After(now):
+		return RoundStatusUpcoming
+	case !round.EndTime.After(now):
+		return RoundStatusEnded
+	case round.Cap.Sign() > 0 && round.Raised.Cmp(round.Cap) >= 0:
+		return RoundStatusFilled
+	default:
+		return RoundStatusActive
+	}
+}
+
+// setRoundStats fills in the raise of a round with its progress and status
+func setRoundStats(round *PresaleRound, raised *big.Int, contributors int, now time.Time) {
+	round.Raised = NewBigInt(raised)
+	round.Contributors = contributors
+	round.ProgressBps = 0
+	if cap := round.Cap.Big(); cap.Sign() > 0 {
+		progress := new(big.Int).Div(new(big.Int).Mul(raised, big.NewInt(10000)), cap)
+		if progress.IsInt64() && progress.Int64() <= math.MaxInt32 {
+			round.ProgressBps = int(progress.Int64())
+		} else {
+			round.ProgressBps = math.MaxInt32
+		}
+	}
+	round.Status = RoundStatus(round, now)
+}
+
+// IsPresaleStatus reports whether status is a presale status
+func IsPresaleStatus(status string) bool {
+	for _, known := range PresaleStatuses {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenRepository persists deployed tokens
+type TokenRepository interface {
+	// Create stores a token and fills in its ID and CreatedAt
+	Create(token *Token) error
+	// GetByAddress gets a token by contract address
+	GetByAddress(address string) (*Token, error)
+	// Exists reports whether a token with the address is stored
+	Exists(address string) (bool, error)
+	// List returns a page of tokens and the cursor of the next page, if any
+	List(query *TokenQuery) ([]*Token, *Cursor, error)
+	// Search finds tokens by name, symbol, address or creator, best matches first
+	Search(term string, limit int) ([]*TokenSearchResult, error)
+}
+
+// PresaleRepository persists presales
+type PresaleRepository interface {
+	// Create stores a presale with its rounds, fills in its ID and
+	// timestamps and records its initial status in the status history
+	Create(presale *Presale) error
+	// GetByID gets a presale by ID with its rounds
+	GetByID(id int) (*Presale, error)
+	// List returns a page of presales and the cursor of the next page, if any
+	List(query *PresaleQuery) ([]*Presale, *Cursor, error)
+	// Search finds presales by their addresses or their token, best matches first
+	Search(term string, limit int) ([]*PresaleSearchResult, error)
+	// Transition stores change.ToStatus as the presale's status and records
+	// the change in its history, if check accepts the presale as currently
+	// stored. The presale is locked meanwhile, so transitions do not race.
+	Transition(change *PresaleStatusChange, check TransitionFunc) (*Presale, error)
+	// StatusHistory lists the status changes of a presale, oldest first
+	StatusHistory(presaleID int) ([]*PresaleStatusChange, error)
+}
+
+// TransitionFunc validates a change to a presale, such as a status change,
+// against the presale as currently stored. Returning an error leaves the
+// presale unchanged.
+type TransitionFunc func(presale *Presale) error
+
+// ReserveFunc decides the participation to store in a presale, given the
+// presale as currently stored with its rounds, its remaining hard-cap
+// capacity and what the participant has contributed to it so far. Returning
+// an error stores nothing.
+type ReserveFunc func(presale *Presale, remaining, contributed *big.Int) (*PresaleParticipation, error)
+
+// ParticipationRepository persists presale participations
+type ParticipationRepository interface {
+	// Reserve stores the participation of participantAddress returned by
+	// reserve while holding the presale's lock, so concurrent contributions
+	// cannot overshoot the hard cap or a per-address limit. It returns the
+	// stored participation and the capacity left after it.
+	Reserve(presaleID int, participantAddress string, reserve ReserveFunc) (*PresaleParticipation, BigInt, error)
+	// Contributed sums the contributions of an address to a presale
+	Contributed(presaleID int, participantAddress string) (BigInt, error)
+	// ListByParticipants lists participations made by any of the addresses, newest first
+	ListByParticipants(participantAddresses []string) ([]*PresaleParticipation, error)
+	// Portfolio sums the participations of the addresses per presale, most
+	// recent contribution first
+	Portfolio(participantAddresses []string) ([]*PortfolioPosition, error)
+	// Contributors sums the participations of a presale per address
+	Contributors(query *ContributorQuery) ([]*Contributor, error)
+}
+
+// WhitelistRepository persists the whitelists of whitelisted presales
+type WhitelistRepository interface {
+	// Replace stores entries as the whitelist of a presale and root as its
+	// Merkle root, replacing any previous whitelist, if check accepts the
+	// presale as currently stored. The presale is locked meanwhile.
+	Replace(presaleID int, root string, entries []*WhitelistEntry, check TransitionFunc) (*Presale, error)
+	// Entry gets the whitelist entry of an address, ErrNotFound if the
+	// address is not on the presale's whitelist
+	Entry(presaleID int, address string) (*WhitelistEntry, error)
+}
+
+// ClaimFunc decides the claim to store for an address of a vesting presale,
+// given the presale as currently stored and the address's vesting balance.
+// Returning an error stores nothing.
+type ClaimFunc func(presale *Presale, balance *VestingBalance) (*VestingClaim, error)
+
+// VestingRepository persists the token claims of vesting presales
+type VestingRepository interface {
+	// Balances sums the tokens bought from a presale and claimed per
+	// address, largest purchase first
+	Balances(presaleID int) ([]*VestingBalance, error)
+	// Balance sums the tokens an address bought from a presale and claimed
+	Balance(presaleID int, address string) (*VestingBalance, error)
+	// Claim stores the claim of participantAddress returned by claim while
+	// holding the presale's lock, so concurrent claims cannot take more
+	// than has vested
+	Claim(presaleID int, participantAddress string, claim ClaimFunc) (*VestingClaim, error)
+}
+
+// Repositories groups the repositories of one store
+type Repositories struct {
+	Tokens         TokenRepository
+	Presales       PresaleRepository
+	Participations ParticipationRepository
+	Whitelists     WhitelistRepository
+	Vesting        VestingRepository
+}
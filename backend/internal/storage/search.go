@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Markers that ts_headline puts around matches. Headlines are HTML-escaped
+// first and only then are the markers turned into <mark> tags, so token
+// names cannot inject markup.
+const (
+	headlineStart = "\x01"
+	headlineStop  = "\x02"
+
+	headlineOptions = `StartSel="` + headlineStart + `", StopSel="` + headlineStop + `", HighlightAll=true`
+)
+
+// SearchHit holds the ranking of a search result
+type SearchHit struct {
+	ExactMatch bool    `json:"exact_match"` // the term is the token, presale or creator address
+	Rank       float64 `json:"rank"`
+	Headline   string  `json:"headline"` // HTML-escaped symbol and name with matches in <mark>
+}
+
+// TokenSearchResult is a token matching a search term
+type TokenSearchResult struct {
+	Token *Token `json:"token"`
+	SearchHit
+}
+
+// PresaleSearchResult is a presale whose token or addresses match a search term
+type PresaleSearchResult struct {
+	Presale *Presale `json:"presale"`
+	SearchHit
+}
+
+// searchTSQuery builds a to_tsquery expression that prefix-matches every
+// word of a term, so "pep co" finds "Pepe Coin". It returns an empty string
+// when the term has no words.
+func searchTSQuery(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
+
+// finishHeadline HTML-escapes a headline and turns its markers into <mark> tags
+func finishHeadline(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, headlineStart, "<mark>")
+	return strings.ReplaceAll(escaped, headlineStop, "</mark>")
+}
+
+// memoryHeadline highlights case-insensitive occurrences of term in text
+// the way ts_headline output looks after finishHeadline
+func memoryHeadline(text, term string) string {
+	lowerText, lowerTerm := strings.ToLower(text), strings.ToLower(term)
+	if lowerTerm == "" || len(lowerText) != len(text) {
+		return finishHeadline(text)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lowerText, lowerTerm)
+		if i < 0 {
+			b.WriteString(text)
+			break
+		}
+		end := i + len(lowerTerm)
+		b.WriteString(text[:i])
+		b.WriteString(headlineStart + text[i:end] + headlineStop)
+		text, lowerText = text[end:], lowerText[end:]
+	}
+
+	return finishHeadline(b.String())
+}
+
+// memoryTokenHit ranks a token for the in-memory repositories: exact address
+// matches, then symbol matches, then name matches. ok is false for no match.
+func memoryTokenHit(token *Token, term string) (hit SearchHit, ok bool) {
+	lowerTerm := strings.ToLower(term)
+	hit.Headline = memoryHeadline(token.Symbol+" "+token.Name, term)
+
+	switch {
+	case strings.EqualFold(token.Address, term) || strings.EqualFold(token.CreatorAddress, term):
+		hit.ExactMatch, hit.Rank = true, 1
+	case strings.EqualFold(token.Symbol, term):
+		hit.Rank = 1
+	case strings.Contains(strings.ToLower(token.Symbol), lowerTerm):
+		hit.Rank = 0.75
+	case strings.Contains(strings.ToLower(token.Name), lowerTerm):
+		hit.Rank = 0.5
+	default:
+		return hit, false
+	}
+
+	return hit, true
+}
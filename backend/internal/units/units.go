@@ -0,0 +1,139 @@
+// Package units parses and formats token and ETH amounts. Amounts are kept
+// in base units (wei, or the smallest unit of a token) and converted to and
+// from human-readable decimals with an explicit number of decimals.
+package units
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Unit is a named denomination of ETH
+type Unit struct {
+	Symbol   string
+	Decimals int
+}
+
+// ETH denominations
+var (
+	Wei   = Unit{Symbol: "wei", Decimals: 0}
+	Gwei  = Unit{Symbol: "gwei", Decimals: 9}
+	Ether = Unit{Symbol: "ETH", Decimals: 18}
+)
+
+// ethUnits maps lowercase unit suffixes to denominations
+var ethUnits = map[string]Unit{
+	"wei":   Wei,
+	"gwei":  Gwei,
+	"eth":   Ether,
+	"ether": Ether,
+}
+
+// ParseUnits parses a non-negative decimal amount such as "1.5" into base
+// units of a token with the given decimals. It fails rather than round when
+// the amount has more fractional digits than decimals.
+func ParseUnits(amount string, decimals int) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	whole, fraction, hasPoint := strings.Cut(amount, ".")
+
+	if !isDigits(whole) || !isDigits(fraction) || whole+fraction == "" || (hasPoint && fraction == "") {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("amount %q has more than %d decimal places", amount, decimals)
+	}
+
+	value, _ := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	return value, nil
+}
+
+// ParseETH parses an ETH amount with an optional unit, such as "1.5 ETH",
+// "250 gwei" or "1000 wei", into wei. Amounts without a unit are read in
+// defaultUnit.
+func ParseETH(amount string, defaultUnit Unit) (*big.Int, error) {
+	fields := strings.Fields(amount)
+
+	switch len(fields) {
+	case 1:
+		value, err := ParseUnits(fields[0], defaultUnit.Decimals)
+		if err != nil && defaultUnit == Wei && strings.Contains(fields[0], ".") {
+			return nil, fmt.Errorf("%w, or name a unit such as %q", err, fields[0]+" ETH")
+		}
+		return value, err
+	case 2:
+		unit, ok := ethUnits[strings.ToLower(fields[1])]
+		if !ok {
+			return nil, fmt.Errorf("unknown unit %q", fields[1])
+		}
+		return ParseUnits(fields[0], unit.Decimals)
+	default:
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+}
+
+// ParseToken parses an amount of a token with an optional unit, the token's
+// symbol, such as "100 USDC", into base units. Amounts without a unit are
+// read in base units.
+func ParseToken(amount string, token Unit) (*big.Int, error) {
+	fields := strings.Fields(amount)
+
+	switch len(fields) {
+	case 1:
+		value, err := ParseUnits(fields[0], 0)
+		if err != nil && strings.Contains(fields[0], ".") {
+			return nil, fmt.Errorf("%w, or name a unit such as %q", err, fields[0]+" "+token.Symbol)
+		}
+		return value, err
+	case 2:
+		if !strings.EqualFold(fields[1], token.Symbol) {
+			return nil, fmt.Errorf("unknown unit %q", fields[1])
+		}
+		return ParseUnits(fields[0], token.Decimals)
+	default:
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+}
+
+// FormatUnits formats base units of a token with the given decimals as a
+// decimal string without trailing zeros
+func FormatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		amount = new(big.Int)
+	}
+
+	base := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, fraction := new(big.Int).QuoRem(amount, base, new(big.Int))
+
+	if fraction.Sign() == 0 {
+		return whole.String()
+	}
+
+	digits := fmt.Sprintf("%0*s", decimals, new(big.Int).Abs(fraction).String())
+	if whole.Sign() == 0 && amount.Sign() < 0 {
+		return "-0." + strings.TrimRight(digits, "0")
+	}
+	return whole.String() + "." + strings.TrimRight(digits, "0")
+}
+
+// FormatETH formats wei as an ETH amount, such as "1.5 ETH"
+func FormatETH(wei *big.Int) string {
+	return FormatUnits(wei, Ether.Decimals) + " " + Ether.Symbol
+}
+
+// FormatAmount formats base units of a currency with its symbol, such as
+// "100 USDC"
+func FormatAmount(amount *big.Int, unit Unit) string {
+	return FormatUnits(amount, unit.Decimals) + " " + unit.Symbol
+}
+
+// isDigits reports whether s consists of ASCII digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
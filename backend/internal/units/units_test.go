@@ -0,0 +1,180 @@
+package units
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals int
+		want     string
+		wantErr  bool
+	}{
+		{name: "whole", amount: "15", decimals: 18, want: "15000000000000000000"},
+		{name: "fraction", amount: "1.5", decimals: 18, want: "1500000000000000000"},
+		{name: "leading point", amount: ".5", decimals: 1, want: "5"},
+		{name: "all decimals used", amount: "0.000001", decimals: 6, want: "1"},
+		{name: "surrounding spaces", amount: "  2.25 ", decimals: 2, want: "225"},
+		{name: "zero decimals", amount: "42", decimals: 0, want: "42"},
+		{name: "zero decimals fraction", amount: "4.2", decimals: 0, wantErr: true},
+		{name: "36 decimals", amount: "1." + strings.Repeat("0", 35) + "1", decimals: 36, want: "1" + strings.Repeat("0", 35) + "1"},
+		{name: "too many fractional digits", amount: "0.1234567", decimals: 6, wantErr: true},
+		{name: "negative", amount: "-1", decimals: 18, wantErr: true},
+		{name: "empty", amount: "", decimals: 18, wantErr: true},
+		{name: "blank", amount: "   ", decimals: 18, wantErr: true},
+		{name: "point only", amount: ".", decimals: 18, wantErr: true},
+		{name: "trailing point", amount: "1.", decimals: 18, wantErr: true},
+		{name: "two points", amount: "1.2.3", decimals: 18, wantErr: true},
+		{name: "exponent", amount: "1e18", decimals: 18, wantErr: true},
+		{name: "inner space", amount: "1 000", decimals: 18, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnits(tt.amount, tt.decimals)
+			checkParse(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestParseETH(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      string
+		defaultUnit Unit
+		want        string
+		wantErr     bool
+	}{
+		{name: "ETH", amount: "1.5 ETH", defaultUnit: Wei, want: "1500000000000000000"},
+		{name: "ether lowercase", amount: "2 ether", defaultUnit: Wei, want: "2000000000000000000"},
+		{name: "gwei", amount: "250 gwei", defaultUnit: Wei, want: "250000000000"},
+		{name: "fractional gwei", amount: "0.5 GWEI", defaultUnit: Wei, want: "500000000"},
+		{name: "wei", amount: "1000 wei", defaultUnit: Ether, want: "1000"},
+		{name: "bare integer in wei", amount: "1000", defaultUnit: Wei, want: "1000"},
+		{name: "bare integer in ETH", amount: "3", defaultUnit: Ether, want: "3000000000000000000"},
+		{name: "surrounding spaces", amount: "  1.5   ETH  ", defaultUnit: Wei, want: "1500000000000000000"},
+		{name: "bare fraction in wei", amount: "1.5", defaultUnit: Wei, wantErr: true},
+		{name: "fractional wei", amount: "1.5 wei", defaultUnit: Wei, wantErr: true},
+		{name: "too many fractional digits", amount: "0.0000000001 gwei", defaultUnit: Wei, wantErr: true},
+		{name: "unknown unit", amount: "1 finney", defaultUnit: Wei, wantErr: true},
+		{name: "negative", amount: "-1 ETH", defaultUnit: Wei, wantErr: true},
+		{name: "empty", amount: "", defaultUnit: Wei, wantErr: true},
+		{name: "extra field", amount: "1 ETH now", defaultUnit: Wei, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseETH(tt.amount, tt.defaultUnit)
+			checkParse(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestParseETHSuggestsUnit(t *testing.T) {
+	_, err := ParseETH("1.5", Wei)
+	if err == nil || !strings.Contains(err.Error(), `"1.5 ETH"`) {
+		t.Fatalf("ParseETH(1.5) error = %v, want a hint to name a unit", err)
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	usdc := Unit{Symbol: "USDC", Decimals: 6}
+
+	tests := []struct {
+		name    string
+		amount  string
+		token   Unit
+		want    string
+		wantErr bool
+	}{
+		{name: "symbol", amount: "100 USDC", token: usdc, want: "100000000"},
+		{name: "symbol any case", amount: "0.25 usdc", token: usdc, want: "250000"},
+		{name: "bare integer in base units", amount: "100", token: usdc, want: "100"},
+		{name: "surrounding spaces", amount: " 1 USDC ", token: usdc, want: "1000000"},
+		{name: "zero decimals", amount: "7 PTS", token: Unit{Symbol: "PTS"}, want: "7"},
+		{name: "36 decimals", amount: "1 BIG", token: Unit{Symbol: "BIG", Decimals: 36}, want: "1" + strings.Repeat("0", 36)},
+		{name: "bare fraction", amount: "1.5", token: usdc, wantErr: true},
+		{name: "too many fractional digits", amount: "0.0000001 USDC", token: usdc, wantErr: true},
+		{name: "ETH is not the token", amount: "1 ETH", token: usdc, wantErr: true},
+		{name: "negative", amount: "-5 USDC", token: usdc, wantErr: true},
+		{name: "empty", amount: "", token: usdc, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseToken(tt.amount, tt.token)
+			checkParse(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals int
+		want     string
+	}{
+		{name: "zero", amount: "0", decimals: 18, want: "0"},
+		{name: "whole", amount: "2000000000000000000", decimals: 18, want: "2"},
+		{name: "trailing zeros trimmed", amount: "1500000000000000000", decimals: 18, want: "1.5"},
+		{name: "smallest unit", amount: "1", decimals: 18, want: "0.000000000000000001"},
+		{name: "zero decimals", amount: "42", decimals: 0, want: "42"},
+		{name: "36 decimals", amount: "1" + strings.Repeat("0", 35) + "5", decimals: 36, want: "1." + strings.Repeat("0", 35) + "5"},
+		{name: "negative fraction", amount: "-5", decimals: 1, want: "-0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, _ := new(big.Int).SetString(tt.amount, 10)
+			got := FormatUnits(amount, tt.decimals)
+			if got != tt.want {
+				t.Fatalf("FormatUnits(%s, %d) = %q, want %q", tt.amount, tt.decimals, got, tt.want)
+			}
+
+			if amount.Sign() < 0 {
+				return
+			}
+			back, err := ParseUnits(got, tt.decimals)
+			if err != nil {
+				t.Fatalf("ParseUnits(%q, %d) error = %v", got, tt.decimals, err)
+			}
+			if back.Cmp(amount) != 0 {
+				t.Fatalf("round trip of %s gave %s", tt.amount, back)
+			}
+		})
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	if got := FormatETH(big.NewInt(1e17)); got != "0.1 ETH" {
+		t.Fatalf("FormatETH = %q, want %q", got, "0.1 ETH")
+	}
+	if got := FormatAmount(big.NewInt(12500000), Unit{Symbol: "USDC", Decimals: 6}); got != "12.5 USDC" {
+		t.Fatalf("FormatAmount = %q, want %q", got, "12.5 USDC")
+	}
+	if got := FormatUnits(nil, 18); got != "0" {
+		t.Fatalf("FormatUnits(nil) = %q, want %q", got, "0")
+	}
+}
+
+func checkParse(t *testing.T, got *big.Int, err error, want string, wantErr bool) {
+	t.Helper()
+
+	if wantErr {
+		if err == nil {
+			t.Fatalf("got %s, want an error", got)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.String() != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
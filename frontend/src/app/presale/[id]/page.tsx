@@ -3,7 +3,7 @@
 import { useState, useEffect, useCallback } from 'react'
 import { useAccount, useConnect } from 'wagmi'
 import { apiClient } from '@/lib/api'
-import { PresaleData } from '@/types'
+import { PresaleData, ParticipationQuote, VestingPosition, WhitelistProof } from '@/types'
 
 interface PresalePageProps {
   params: Promise<{ id: string }>
@@ -20,6 +20,11 @@ export default function PresalePage({ params }: PresalePageProps) {
   const [purchasing, setPurchasing] = useState(false)
   const [purchaseSuccess, setPurchaseSuccess] = useState<string | null>(null)
   const [presaleId, setPresaleId] = useState<string>('')
+  const [quote, setQuote] = useState<ParticipationQuote | null>(null)
+  const [whitelistProof, setWhitelistProof] = useState<WhitelistProof | null>(null)
+  const [notWhitelisted, setNotWhitelisted] = useState(false)
+  const [vestingPosition, setVestingPosition] = useState<VestingPosition | null>(null)
+  const [claiming, setClaiming] = useState(false)
 
   const loadPresaleData = useCallback(async (id: string) => {
     try {
@@ -33,6 +38,15 @@ export default function PresalePage({ params }: PresalePageProps) {
     }
   }, [])
 
+  const loadVestingPosition = useCallback(async (id: number, wallet: string) => {
+    try {
+      const response = await apiClient.getVestingPosition(id, wallet)
+      setVestingPosition(response.data)
+    } catch {
+      setVestingPosition(null)
+    }
+  }, [])
+
   useEffect(() => {
     params.then((resolvedParams) => {
       setPresaleId(resolvedParams.id)
@@ -62,12 +76,15 @@ export default function PresalePage({ params }: PresalePageProps) {
       
       const response = await apiClient.participateInPresale(
         parseInt(presaleId),
-        purchaseAmount,
+        `${purchaseAmount} ${presaleData?.presale.payment_symbol ?? 'ETH'}`,
         mockTxHash
       )
 
-      setPurchaseSuccess(`Purchase successful! You will receive ${response.data.amount_tokens} tokens.`)
+      setPurchaseSuccess(`Purchase successful! You will receive ${response.data.participation.amount_tokens_formatted} tokens.`)
       setPurchaseAmount('')
+      if (presaleData?.presale.vesting) {
+        loadVestingPosition(parseInt(presaleId), address)
+      }
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Purchase failed')
     } finally {
@@ -75,27 +92,73 @@ export default function PresalePage({ params }: PresalePageProps) {
     }
   }
 
-  const calculateTokens = () => {
-    if (!purchaseAmount || !presaleData) return '0'
-    const ethAmount = Number(purchaseAmount)
-    const rate = Number(presaleData.presale.rate)
-    return (ethAmount * rate).toLocaleString()
-  }
+  // Token math follows the contract exactly, so the backend quotes it
+  useEffect(() => {
+    setQuote(null)
+    if (!presaleId || !purchaseAmount) return
 
-  const isPresaleActive = () => {
-    if (!presaleData) return false
-    const now = new Date()
-    const deadline = new Date(presaleData.presale.deadline)
-    return presaleData.presale.active && now < deadline
+    const timer = setTimeout(() => {
+      apiClient.getPresaleQuote(parseInt(presaleId), `${purchaseAmount} ${presaleData?.presale.payment_symbol ?? 'ETH'}`, address ?? '')
+        .then((response) => setQuote(response.data))
+        .catch(() => setQuote(null))
+    }, 300)
+
+    return () => clearTimeout(timer)
+  }, [presaleId, purchaseAmount, address, presaleData?.presale.payment_symbol])
+
+  // Whitelisted presales only sell to wallets on their whitelist
+  useEffect(() => {
+    setWhitelistProof(null)
+    setNotWhitelisted(false)
+    if (!presaleData?.presale.whitelisted || !address) return
+
+    apiClient.getWhitelistProof(presaleData.presale.id, address)
+      .then((response) => setWhitelistProof(response.data))
+      .catch(() => setNotWhitelisted(true))
+  }, [presaleData, address])
+
+  // Vesting presales hold bought tokens until buyers claim them
+  useEffect(() => {
+    setVestingPosition(null)
+    if (!presaleData?.presale.vesting || !address) return
+
+    loadVestingPosition(presaleData.presale.id, address)
+  }, [presaleData, address, loadVestingPosition])
+
+  const handleClaim = async () => {
+    setClaiming(true)
+    setPurchaseSuccess(null)
+    setError(null)
+
+    try {
+      // In a real implementation, you would call claim() on the presale contract
+      const mockTxHash = '0x' + Math.random().toString(16).substr(2, 64)
+
+      const response = await apiClient.claimVestedTokens(parseInt(presaleId), mockTxHash)
+      setVestingPosition(response.data.position)
+      setPurchaseSuccess(`Claimed ${response.data.claim.amount_tokens_formatted} tokens.`)
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Claim failed')
+    } finally {
+      setClaiming(false)
+    }
   }
 
+  const formatVestingDays = (seconds: number) => `${Math.round((seconds / 86400) * 10) / 10}d`
+
+  // The backend computes the status from the deadline and the raise
+  const isPresaleActive = () => presaleData?.presale.status === 'live'
+
+  // A scheduled presale counts down to its start time, others to the deadline
+  const isScheduled = () => presaleData?.presale.status === 'scheduled'
+
   const getTimeRemaining = () => {
     if (!presaleData) return ''
     const now = new Date()
-    const deadline = new Date(presaleData.presale.deadline)
-    const diff = deadline.getTime() - now.getTime()
+    const target = new Date(isScheduled() ? presaleData.presale.start_time : presaleData.presale.deadline)
+    const diff = target.getTime() - now.getTime()
     
-    if (diff <= 0) return 'Expired'
+    if (diff <= 0) return isScheduled() ? 'Starting' : 'Expired'
     
     const days = Math.floor(diff / (1000 * 60 * 60 * 24))
     const hours = Math.floor((diff % (1000 * 60 * 60 * 24)) / (1000 * 60 * 60))
@@ -156,22 +219,81 @@ export default function PresalePage({ params }: PresalePageProps) {
               </div>
               <div className="bg-gray-50 p-4 rounded-lg">
                 <h3 className="text-sm font-medium text-gray-500">Rate</h3>
-                <p className="text-sm text-gray-900">{presaleData?.presale.rate} tokens per ETH</p>
+                <p className="text-sm text-gray-900">{presaleData?.presale.rate_formatted} tokens per {presaleData?.presale.payment_symbol}</p>
               </div>
               <div className="bg-gray-50 p-4 rounded-lg">
-                <h3 className="text-sm font-medium text-gray-500">Time Remaining</h3>
+                <h3 className="text-sm font-medium text-gray-500">{isScheduled() ? 'Starts In' : 'Time Remaining'}</h3>
                 <p className="text-sm text-gray-900">{getTimeRemaining()}</p>
               </div>
+              <div className="bg-gray-50 p-4 rounded-lg">
+                <h3 className="text-sm font-medium text-gray-500">Start Time</h3>
+                <p className="text-sm text-gray-900">{presaleData && new Date(presaleData.presale.start_time).toLocaleString()}</p>
+              </div>
+              <div className="bg-gray-50 p-4 rounded-lg">
+                <h3 className="text-sm font-medium text-gray-500">Deadline</h3>
+                <p className="text-sm text-gray-900">{presaleData && new Date(presaleData.presale.deadline).toLocaleString()}</p>
+              </div>
               <div className="bg-gray-50 p-4 rounded-lg">
                 <h3 className="text-sm font-medium text-gray-500">Soft Cap</h3>
-                <p className="text-sm text-gray-900">{presaleData?.presale.soft_cap} ETH</p>
+                <p className="text-sm text-gray-900">{presaleData?.presale.soft_cap_formatted}</p>
               </div>
               <div className="bg-gray-50 p-4 rounded-lg">
                 <h3 className="text-sm font-medium text-gray-500">Hard Cap</h3>
-                <p className="text-sm text-gray-900">{presaleData?.presale.hard_cap} ETH</p>
+                <p className="text-sm text-gray-900">{presaleData?.presale.hard_cap_formatted}</p>
               </div>
+              {presaleData?.presale.vesting && (
+                <div className="bg-gray-50 p-4 rounded-lg">
+                  <h3 className="text-sm font-medium text-gray-500">Vesting</h3>
+                  <p className="text-sm text-gray-900">
+                    {presaleData.presale.vesting_tge_bps / 100}% at TGE, {formatVestingDays(presaleData.presale.vesting_cliff_seconds)} cliff, {formatVestingDays(presaleData.presale.vesting_duration_seconds)} linear
+                  </p>
+                </div>
+              )}
+              {presaleData && presaleData.presale.min_contribution !== '0' && (
+                <div className="bg-gray-50 p-4 rounded-lg">
+                  <h3 className="text-sm font-medium text-gray-500">Min per Wallet</h3>
+                  <p className="text-sm text-gray-900">{presaleData.presale.min_contribution_formatted}</p>
+                </div>
+              )}
+              {presaleData && presaleData.presale.max_contribution !== '0' && (
+                <div className="bg-gray-50 p-4 rounded-lg">
+                  <h3 className="text-sm font-medium text-gray-500">Max per Wallet</h3>
+                  <p className="text-sm text-gray-900">{presaleData.presale.max_contribution_formatted}</p>
+                </div>
+              )}
             </div>
 
+            {/* Rounds */}
+            {presaleData?.presale.rounds && presaleData.presale.rounds.length > 0 && (
+              <div className="mb-6">
+                <h3 className="text-sm font-medium text-gray-500 mb-2">Rounds</h3>
+                <div className="space-y-3">
+                  {presaleData.presale.rounds.map((round) => (
+                    <div key={round.index} className="bg-gray-50 p-4 rounded-lg">
+                      <div className="flex justify-between text-sm">
+                        <span className="font-medium text-gray-900">{round.name}</span>
+                        <span className="text-gray-500">{round.status}</span>
+                      </div>
+                      <p className="text-sm text-gray-600">
+                        {round.rate} tokens per ETH, {new Date(round.start_time).toLocaleString()} – {new Date(round.end_time).toLocaleString()}
+                      </p>
+                      <p className="text-sm text-gray-600">
+                        {round.raised_formatted} raised{round.cap !== '0' && ` of ${round.cap_formatted}`} by {round.contributors} contributors
+                      </p>
+                      {round.cap !== '0' && (
+                        <div className="mt-2 w-full bg-gray-200 rounded-full h-2">
+                          <div
+                            className="bg-indigo-600 h-2 rounded-full"
+                            style={{ width: `${Math.min(round.progress_bps / 100, 100)}%` }}
+                          ></div>
+                        </div>
+                      )}
+                    </div>
+                  ))}
+                </div>
+              </div>
+            )}
+
             {/* Status */}
             <div className="mb-6">
               <span className={`inline-flex items-center px-3 py-1 rounded-full text-sm font-medium ${
@@ -179,7 +301,7 @@ export default function PresalePage({ params }: PresalePageProps) {
                   ? 'bg-green-100 text-green-800'
                   : 'bg-red-100 text-red-800'
               }`}>
-                {isPresaleActive() ? 'Active' : 'Inactive'}
+                {presaleData?.presale.status.replace('_', ' ')}
               </span>
             </div>
 
@@ -201,11 +323,16 @@ export default function PresalePage({ params }: PresalePageProps) {
                       </button>
                     ))}
                   </div>
+                ) : notWhitelisted ? (
+                  <p className="text-sm text-gray-600">This presale is whitelisted and your wallet is not on the whitelist</p>
                 ) : (
                   <form onSubmit={handlePurchase} className="space-y-4">
+                    {whitelistProof && whitelistProof.allocation !== '0' && (
+                      <p className="text-sm text-gray-600">Your allocation: {whitelistProof.allocation_formatted}</p>
+                    )}
                     <div>
                       <label htmlFor="amount" className="block text-sm font-medium text-gray-700">
-                        Amount (ETH)
+                        Amount ({presaleData?.presale.payment_symbol ?? 'ETH'})
                       </label>
                       <input
                         type="number"
@@ -218,9 +345,39 @@ export default function PresalePage({ params }: PresalePageProps) {
                         className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
                         required
                       />
-                      {purchaseAmount && (
+                      {presaleData?.presale.payment_token && (
+                        <p className="mt-1 text-sm text-gray-500">
+                          Approve {presaleData.presale.payment_symbol} to the presale before buying
+                        </p>
+                      )}
+                      {quote && (
                         <p className="mt-1 text-sm text-gray-500">
-                          You will receive approximately {calculateTokens()} tokens
+                          You will receive {quote.tokens_formatted} tokens{quote.round && ` at the ${quote.round.name} rate`}
+                        </p>
+                      )}
+                      {quote?.exceeds_round_cap && (
+                        <p className="mt-1 text-sm text-red-600">
+                          This amount exceeds what is left of the {quote.round?.name} round
+                        </p>
+                      )}
+                      {quote?.exceeds_cap && (
+                        <p className="mt-1 text-sm text-red-600">
+                          This amount exceeds the remaining hard cap capacity
+                        </p>
+                      )}
+                      {quote?.exceeds_allocation && (
+                        <p className="mt-1 text-sm text-red-600">
+                          This amount exceeds your whitelist allocation
+                        </p>
+                      )}
+                      {quote?.below_min_contribution && (
+                        <p className="mt-1 text-sm text-red-600">
+                          Your total contribution must be at least {presaleData?.presale.min_contribution_formatted}
+                        </p>
+                      )}
+                      {quote?.exceeds_max_contribution && (
+                        <p className="mt-1 text-sm text-red-600">
+                          Your total contribution must not exceed {presaleData?.presale.max_contribution_formatted}
                         </p>
                       )}
                     </div>
@@ -237,6 +394,46 @@ export default function PresalePage({ params }: PresalePageProps) {
               </div>
             )}
 
+            {/* Vested tokens */}
+            {vestingPosition && vestingPosition.purchased !== '0' && (
+              <div className="border-t pt-6 mt-6">
+                <h3 className="text-lg font-medium text-gray-900 mb-4">Your Tokens</h3>
+                <div className="grid grid-cols-2 gap-4 mb-4">
+                  <div>
+                    <h4 className="text-sm font-medium text-gray-500">Purchased</h4>
+                    <p className="text-sm text-gray-900">{vestingPosition.purchased_formatted}</p>
+                  </div>
+                  <div>
+                    <h4 className="text-sm font-medium text-gray-500">Vested</h4>
+                    <p className="text-sm text-gray-900">{vestingPosition.vested_formatted}</p>
+                  </div>
+                  <div>
+                    <h4 className="text-sm font-medium text-gray-500">Claimed</h4>
+                    <p className="text-sm text-gray-900">{vestingPosition.claimed_formatted}</p>
+                  </div>
+                  <div>
+                    <h4 className="text-sm font-medium text-gray-500">Claimable</h4>
+                    <p className="text-sm text-gray-900">{vestingPosition.claimable_formatted}</p>
+                  </div>
+                </div>
+                {vestingPosition.vesting_start ? (
+                  <p className="text-sm text-gray-600 mb-4">
+                    Fully vested on {vestingPosition.vesting_end && new Date(vestingPosition.vesting_end).toLocaleString()}
+                  </p>
+                ) : (
+                  <p className="text-sm text-gray-600 mb-4">Tokens start vesting when the presale is finalized successfully</p>
+                )}
+                <button
+                  type="button"
+                  onClick={handleClaim}
+                  disabled={claiming || vestingPosition.claimable === '0'}
+                  className="w-full flex justify-center py-2 px-4 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-indigo-600 hover:bg-indigo-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500 disabled:opacity-50"
+                >
+                  {claiming ? 'Claiming...' : 'Claim Tokens'}
+                </button>
+              </div>
+            )}
+
             {/* Messages */}
             {error && (
               <div className="mt-4 bg-red-50 border border-red-200 rounded-md p-4">
@@ -2,7 +2,13 @@
 
 import { useState, useEffect, useCallback } from 'react'
 import { apiClient } from '@/lib/api'
-import { Token, Presale } from '@/types'
+import { Token, Presale, PresaleStatus } from '@/types'
+
+// The next step a creator takes for a presale in each status
+const nextStep: Partial<Record<PresaleStatus, { status: PresaleStatus; label: string }>> = {
+  draft: { status: 'unfunded', label: 'Publish' },
+  unfunded: { status: 'scheduled', label: 'Mark funded' },
+}
 
 export default function Dashboard() {
   const [tokens, setTokens] = useState<Token[]>([])
@@ -31,6 +37,15 @@ export default function Dashboard() {
     loadData()
   }, [loadData])
 
+  const advancePresale = async (presale: Presale, status: PresaleStatus) => {
+    try {
+      const response = await apiClient.transitionPresale(presale.id, status)
+      setPresales((current) => current.map((p) => (p.id === presale.id ? response.data : p)))
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to update presale')
+    }
+  }
+
   if (loading) {
     return (
       <div className="flex justify-center items-center h-64">
@@ -85,7 +100,7 @@ export default function Dashboard() {
                       {token.name} ({token.symbol})
                     </p>
                     <p className="text-sm text-gray-500">
-                      Supply: {token.total_supply} | Address: {token.address}
+                      Supply: {token.total_supply_formatted} | Address: {token.address}
                     </p>
                   </div>
                   <div className="text-sm text-gray-500">
@@ -120,7 +135,7 @@ export default function Dashboard() {
                       Token: {presale.token_address}
                     </p>
                     <p className="text-sm text-gray-500">
-                      Rate: {presale.rate} | Soft Cap: {presale.soft_cap} | Hard Cap: {presale.hard_cap}
+                      Rate: {presale.rate_formatted} per {presale.payment_symbol} | Soft Cap: {presale.soft_cap_formatted} | Hard Cap: {presale.hard_cap_formatted}
                     </p>
                     <p className="text-sm text-gray-500">
                       Deadline: {new Date(presale.deadline).toLocaleDateString()}
@@ -128,10 +143,18 @@ export default function Dashboard() {
                   </div>
                   <div className="flex flex-col items-end">
                     <span className={`inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium ${
-                      presale.active ? 'bg-green-100 text-green-800' : 'bg-gray-100 text-gray-800'
+                      presale.status === 'live' ? 'bg-green-100 text-green-800' : 'bg-gray-100 text-gray-800'
                     }`}>
-                      {presale.active ? 'Active' : 'Inactive'}
+                      {presale.status.replace('_', ' ')}
                     </span>
+                    {nextStep[presale.status] && (
+                      <button
+                        onClick={() => advancePresale(presale, nextStep[presale.status]!.status)}
+                        className="mt-1 text-xs font-medium text-indigo-600 hover:text-indigo-500"
+                      >
+                        {nextStep[presale.status]!.label}
+                      </button>
+                    )}
                     <div className="mt-1 text-sm text-gray-500">
                       {new Date(presale.created_at).toLocaleDateString()}
                     </div>
@@ -2,7 +2,17 @@
 
 import { useState, useEffect, useCallback } from 'react'
 import { apiClient } from '@/lib/api'
-import { Token } from '@/types'
+import { PaymentCurrency, PresaleRoundRequest, Token, VestingSchedule, WhitelistEntry } from '@/types'
+
+interface RoundForm {
+  name: string
+  rate: string
+  cap: string
+  startTime: string
+  endTime: string
+}
+
+const emptyRound = (): RoundForm => ({ name: '', rate: '', cap: '', startTime: '', endTime: '' })
 
 export default function PresaleCreator() {
   const [tokens, setTokens] = useState<Token[]>([])
@@ -11,8 +21,23 @@ export default function PresaleCreator() {
     rate: '',
     softCap: '',
     hardCap: '',
-    deadline: ''
+    minContribution: '',
+    maxContribution: '',
+    startTime: '',
+    deadline: '',
+    whitelisted: false,
+    whitelist: '',
+    vesting: false,
+    tgePercent: '',
+    cliffDays: '',
+    vestingDays: '',
+    tiered: false,
+    payInToken: false,
+    paymentToken: '',
+    paymentSymbol: '',
+    paymentDecimals: ''
   })
+  const [rounds, setRounds] = useState<RoundForm[]>([emptyRound()])
   const [loading, setLoading] = useState(false)
   const [loadingTokens, setLoadingTokens] = useState(true)
   const [error, setError] = useState<string | null>(null)
@@ -33,13 +58,32 @@ export default function PresaleCreator() {
     loadTokens()
   }, [loadTokens])
 
-  const handleInputChange = (e: React.ChangeEvent<HTMLInputElement | HTMLSelectElement>) => {
+  const handleInputChange = (e: React.ChangeEvent<HTMLInputElement | HTMLSelectElement | HTMLTextAreaElement>) => {
+    const { name, value, type } = e.target
     setFormData({
       ...formData,
-      [e.target.name]: e.target.value
+      [name]: type === 'checkbox' ? (e.target as HTMLInputElement).checked : value
     })
   }
 
+  // Caps, limits and rates are entered in whole units of this currency
+  const currency = formData.payInToken && formData.paymentSymbol ? formData.paymentSymbol : 'ETH'
+
+  const handleRoundChange = (index: number, field: keyof RoundForm, value: string) => {
+    setRounds(rounds.map((round, i) => (i === index ? { ...round, [field]: value } : round)))
+  }
+
+  // One "address" or "address, allocation in ETH" per line
+  const parseWhitelist = (text: string): WhitelistEntry[] =>
+    text
+      .split('\n')
+      .map((line) => line.trim())
+      .filter((line) => line !== '')
+      .map((line) => {
+        const [address, allocation = ''] = line.split(',').map((part) => part.trim())
+        return { address, allocation: allocation ? `${allocation} ETH` : '' }
+      })
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault()
     setLoading(true)
@@ -48,11 +92,12 @@ export default function PresaleCreator() {
 
     try {
       // Validate form
-      if (!formData.tokenAddress || !formData.rate || !formData.softCap || !formData.hardCap || !formData.deadline) {
+      // Multi-round presales default the rate to the first round's
+      if (!formData.tokenAddress || (!formData.rate && !formData.tiered) || !formData.softCap || !formData.hardCap || !formData.deadline) {
         throw new Error('All fields are required')
       }
 
-      if (isNaN(Number(formData.rate)) || Number(formData.rate) <= 0) {
+      if (formData.rate && (isNaN(Number(formData.rate)) || Number(formData.rate) <= 0)) {
         throw new Error('Rate must be a positive number')
       }
 
@@ -64,19 +109,136 @@ export default function PresaleCreator() {
         throw new Error('Hard cap must be greater than soft cap')
       }
 
+      const minContribution = formData.minContribution ? Number(formData.minContribution) : 0
+      const maxContribution = formData.maxContribution ? Number(formData.maxContribution) : 0
+      if (isNaN(minContribution) || minContribution < 0 || isNaN(maxContribution) || maxContribution < 0) {
+        throw new Error('Contribution limits must be positive numbers')
+      }
+
+      if (minContribution > Number(formData.hardCap) || maxContribution > Number(formData.hardCap)) {
+        throw new Error('Contribution limits must not exceed the hard cap')
+      }
+
+      if (maxContribution > 0 && minContribution > maxContribution) {
+        throw new Error('Min contribution must not exceed max contribution')
+      }
+
+      if (formData.whitelisted && (minContribution > 0 || maxContribution > 0)) {
+        throw new Error('Whitelisted presales limit contributions with allocations')
+      }
+
       const deadlineDate = new Date(formData.deadline)
       if (deadlineDate <= new Date()) {
         throw new Error('Deadline must be in the future')
       }
 
+      const startDate = formData.startTime ? new Date(formData.startTime) : null
+      if (startDate && startDate >= deadlineDate) {
+        throw new Error('Start time must be before the deadline')
+      }
+
+      const whitelist = formData.whitelisted ? parseWhitelist(formData.whitelist) : []
+      if (formData.whitelisted && whitelist.length === 0) {
+        throw new Error('Add at least one whitelisted address')
+      }
+
+      let vesting: VestingSchedule | null = null
+      if (formData.vesting) {
+        const tgePercent = Number(formData.tgePercent || 0)
+        const cliffDays = Number(formData.cliffDays || 0)
+        const vestingDays = Number(formData.vestingDays || 0)
+        if (isNaN(tgePercent) || tgePercent < 0 || tgePercent >= 100) {
+          throw new Error('TGE unlock must be at least 0% and below 100%')
+        }
+        if (isNaN(cliffDays) || cliffDays < 0 || isNaN(vestingDays) || vestingDays < 0 || cliffDays + vestingDays <= 0) {
+          throw new Error('Vesting needs a cliff or a vesting period')
+        }
+        if (formData.whitelisted || minContribution > 0 || maxContribution > 0) {
+          throw new Error('Vesting presales cannot be whitelisted or limit contributions')
+        }
+        vesting = {
+          tge_bps: Math.round(tgePercent * 100),
+          cliff_seconds: Math.round(cliffDays * 86400),
+          duration_seconds: Math.round(vestingDays * 86400)
+        }
+      }
+
+      let payment: PaymentCurrency | null = null
+      if (formData.payInToken) {
+        if (formData.whitelisted || minContribution > 0 || maxContribution > 0 || formData.vesting || formData.tiered) {
+          throw new Error('Presales paid in a token cannot be whitelisted, limit contributions, vest or have rounds')
+        }
+        if (!/^0x[0-9a-fA-F]{40}$/.test(formData.paymentToken)) {
+          throw new Error('Payment token must be a token address')
+        }
+        if (formData.paymentToken.toLowerCase() === formData.tokenAddress.toLowerCase()) {
+          throw new Error('Payment token must differ from the token sold')
+        }
+        if (!formData.paymentSymbol || /\s/.test(formData.paymentSymbol)) {
+          throw new Error('Payment token symbol is required')
+        }
+        const decimals = Number(formData.paymentDecimals)
+        if (formData.paymentDecimals === '' || !Number.isInteger(decimals) || decimals < 0 || decimals > 36) {
+          throw new Error('Payment token decimals must be a whole number from 0 to 36')
+        }
+        payment = { token: formData.paymentToken, symbol: formData.paymentSymbol, decimals }
+      }
+
+      const roundRequests: PresaleRoundRequest[] = []
+      if (formData.tiered) {
+        if (formData.whitelisted || minContribution > 0 || maxContribution > 0 || formData.vesting) {
+          throw new Error('Multi-round presales cannot be whitelisted, limit contributions or vest')
+        }
+        let previousEnd: Date | null = null
+        for (const round of rounds) {
+          if (!round.name || !round.rate || !round.startTime || !round.endTime) {
+            throw new Error('Every round needs a name, a rate, a start and an end')
+          }
+          if (isNaN(Number(round.rate)) || Number(round.rate) <= 0) {
+            throw new Error(`Round ${round.name}: rate must be a positive number`)
+          }
+          const cap = round.cap ? Number(round.cap) : 0
+          if (isNaN(cap) || cap < 0 || cap > Number(formData.hardCap)) {
+            throw new Error(`Round ${round.name}: cap must be between 0 and the hard cap`)
+          }
+          const roundStart = new Date(round.startTime)
+          const roundEnd = new Date(round.endTime)
+          if (roundStart >= roundEnd || roundEnd > deadlineDate) {
+            throw new Error(`Round ${round.name}: must start before it ends, and end by the deadline`)
+          }
+          if (previousEnd && roundStart < previousEnd) {
+            throw new Error(`Round ${round.name}: must start after the previous round ends`)
+          }
+          previousEnd = roundEnd
+          roundRequests.push({
+            name: round.name,
+            rate: round.rate,
+            cap: round.cap ? `${round.cap} ETH` : '',
+            start_time: roundStart.toISOString(),
+            end_time: roundEnd.toISOString()
+          })
+        }
+      }
+
       const response = await apiClient.createPresale(
         formData.tokenAddress,
         formData.rate,
-        formData.softCap,
-        formData.hardCap,
-        deadlineDate.toISOString()
+        `${formData.softCap} ${currency}`,
+        `${formData.hardCap} ${currency}`,
+        startDate ? startDate.toISOString() : '',
+        deadlineDate.toISOString(),
+        formData.whitelisted,
+        formData.minContribution ? `${formData.minContribution} ${currency}` : '',
+        formData.maxContribution ? `${formData.maxContribution} ${currency}` : '',
+        vesting,
+        roundRequests,
+        payment
       )
 
+      if (formData.whitelisted) {
+        await apiClient.uploadWhitelist(response.data.presale.id, whitelist)
+      }
+
       setSuccess({
         message: `Presale created successfully! Address: ${response.data.address}`,
         landingUrl: response.data.landing_url
@@ -86,8 +248,23 @@ export default function PresaleCreator() {
         rate: '',
         softCap: '',
         hardCap: '',
-        deadline: ''
+        minContribution: '',
+        maxContribution: '',
+        startTime: '',
+        deadline: '',
+        whitelisted: false,
+        whitelist: '',
+        vesting: false,
+        tgePercent: '',
+        cliffDays: '',
+        vestingDays: '',
+        tiered: false,
+        payInToken: false,
+        paymentToken: '',
+        paymentSymbol: '',
+        paymentDecimals: ''
       })
+      setRounds([emptyRound()])
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Failed to create presale')
     } finally {
@@ -183,9 +360,74 @@ export default function PresaleCreator() {
           )}
         </div>
 
+        <div className="flex items-center">
+          <input
+            type="checkbox"
+            name="payInToken"
+            id="payInToken"
+            checked={formData.payInToken}
+            onChange={handleInputChange}
+            className="h-4 w-4 text-indigo-600 focus:ring-indigo-500 border-gray-300 rounded"
+          />
+          <label htmlFor="payInToken" className="ml-2 block text-sm text-gray-700">
+            Accept payment in an ERC-20 token instead of ETH
+          </label>
+        </div>
+
+        {formData.payInToken && (
+          <div className="space-y-4">
+            <div>
+              <label htmlFor="paymentToken" className="block text-sm font-medium text-gray-700">
+                Payment Token Address
+              </label>
+              <input
+                type="text"
+                name="paymentToken"
+                id="paymentToken"
+                value={formData.paymentToken}
+                onChange={handleInputChange}
+                placeholder="0x..."
+                className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border font-mono"
+              />
+            </div>
+            <div>
+              <label htmlFor="paymentSymbol" className="block text-sm font-medium text-gray-700">
+                Payment Token Symbol
+              </label>
+              <input
+                type="text"
+                name="paymentSymbol"
+                id="paymentSymbol"
+                value={formData.paymentSymbol}
+                onChange={handleInputChange}
+                placeholder="e.g., USDC"
+                className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+              />
+            </div>
+            <div>
+              <label htmlFor="paymentDecimals" className="block text-sm font-medium text-gray-700">
+                Payment Token Decimals
+              </label>
+              <input
+                type="number"
+                step="1"
+                name="paymentDecimals"
+                id="paymentDecimals"
+                value={formData.paymentDecimals}
+                onChange={handleInputChange}
+                placeholder="e.g., 6"
+                className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+              />
+            </div>
+            <p className="text-sm text-gray-500">
+              Buyers approve the token to the presale before buying; raised funds and refunds are paid in it
+            </p>
+          </div>
+        )}
+
         <div>
           <label htmlFor="rate" className="block text-sm font-medium text-gray-700">
-            Rate (tokens per ETH)
+            Rate (tokens per {currency}{formData.tiered ? ', optional' : ''})
           </label>
           <input
             type="number"
@@ -195,13 +437,13 @@ export default function PresaleCreator() {
             onChange={handleInputChange}
             placeholder="e.g., 1000"
             className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
-            required
+            required={!formData.tiered}
           />
         </div>
 
         <div>
           <label htmlFor="softCap" className="block text-sm font-medium text-gray-700">
-            Soft Cap (ETH)
+            Soft Cap ({currency})
           </label>
           <input
             type="number"
@@ -218,7 +460,7 @@ export default function PresaleCreator() {
 
         <div>
           <label htmlFor="hardCap" className="block text-sm font-medium text-gray-700">
-            Hard Cap (ETH)
+            Hard Cap ({currency})
           </label>
           <input
             type="number"
@@ -233,6 +475,56 @@ export default function PresaleCreator() {
           />
         </div>
 
+        <div>
+          <label htmlFor="minContribution" className="block text-sm font-medium text-gray-700">
+            Min Contribution per Wallet ({currency}, optional)
+          </label>
+          <input
+            type="number"
+            step="0.01"
+            name="minContribution"
+            id="minContribution"
+            value={formData.minContribution}
+            onChange={handleInputChange}
+            placeholder="e.g., 0.1"
+            className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+          />
+        </div>
+
+        <div>
+          <label htmlFor="maxContribution" className="block text-sm font-medium text-gray-700">
+            Max Contribution per Wallet ({currency}, optional)
+          </label>
+          <input
+            type="number"
+            step="0.01"
+            name="maxContribution"
+            id="maxContribution"
+            value={formData.maxContribution}
+            onChange={handleInputChange}
+            placeholder="e.g., 2"
+            className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+          />
+        </div>
+
+        <div>
+          <label htmlFor="startTime" className="block text-sm font-medium text-gray-700">
+            Start Time (optional)
+          </label>
+          <input
+            type="datetime-local"
+            name="startTime"
+            id="startTime"
+            value={formData.startTime}
+            onChange={handleInputChange}
+            min={getMinDatetime()}
+            className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+          />
+          <p className="mt-1 text-sm text-gray-500">
+            Leave empty to open as soon as the presale is funded
+          </p>
+        </div>
+
         <div>
           <label htmlFor="deadline" className="block text-sm font-medium text-gray-700">
             Deadline
@@ -249,6 +541,186 @@ export default function PresaleCreator() {
           />
         </div>
 
+        <div className="flex items-center">
+          <input
+            type="checkbox"
+            name="whitelisted"
+            id="whitelisted"
+            checked={formData.whitelisted}
+            onChange={handleInputChange}
+            className="h-4 w-4 text-indigo-600 focus:ring-indigo-500 border-gray-300 rounded"
+          />
+          <label htmlFor="whitelisted" className="ml-2 block text-sm text-gray-700">
+            Whitelisted presale
+          </label>
+        </div>
+
+        {formData.whitelisted && (
+          <div>
+            <label htmlFor="whitelist" className="block text-sm font-medium text-gray-700">
+              Whitelist
+            </label>
+            <textarea
+              name="whitelist"
+              id="whitelist"
+              rows={5}
+              value={formData.whitelist}
+              onChange={handleInputChange}
+              placeholder={'0x1234...abcd, 1.5\n0x5678...ef01'}
+              className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border font-mono"
+            />
+            <p className="mt-1 text-sm text-gray-500">
+              One address per line, optionally followed by a comma and its ETH allocation
+            </p>
+          </div>
+        )}
+
+        <div className="flex items-center">
+          <input
+            type="checkbox"
+            name="vesting"
+            id="vesting"
+            checked={formData.vesting}
+            onChange={handleInputChange}
+            className="h-4 w-4 text-indigo-600 focus:ring-indigo-500 border-gray-300 rounded"
+          />
+          <label htmlFor="vesting" className="ml-2 block text-sm text-gray-700">
+            Vest bought tokens
+          </label>
+        </div>
+
+        {formData.vesting && (
+          <div className="space-y-4">
+            <div>
+              <label htmlFor="tgePercent" className="block text-sm font-medium text-gray-700">
+                TGE Unlock (%)
+              </label>
+              <input
+                type="number"
+                step="0.01"
+                name="tgePercent"
+                id="tgePercent"
+                value={formData.tgePercent}
+                onChange={handleInputChange}
+                placeholder="e.g., 20"
+                className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+              />
+            </div>
+            <div>
+              <label htmlFor="cliffDays" className="block text-sm font-medium text-gray-700">
+                Cliff (days)
+              </label>
+              <input
+                type="number"
+                step="1"
+                name="cliffDays"
+                id="cliffDays"
+                value={formData.cliffDays}
+                onChange={handleInputChange}
+                placeholder="e.g., 30"
+                className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+              />
+            </div>
+            <div>
+              <label htmlFor="vestingDays" className="block text-sm font-medium text-gray-700">
+                Linear Vesting (days)
+              </label>
+              <input
+                type="number"
+                step="1"
+                name="vestingDays"
+                id="vestingDays"
+                value={formData.vestingDays}
+                onChange={handleInputChange}
+                placeholder="e.g., 180"
+                className="mt-1 block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+              />
+            </div>
+            <p className="text-sm text-gray-500">
+              Buyers claim the TGE unlock when the presale is finalized, and the rest releases linearly after the cliff
+            </p>
+          </div>
+        )}
+
+        <div className="flex items-center">
+          <input
+            type="checkbox"
+            name="tiered"
+            id="tiered"
+            checked={formData.tiered}
+            onChange={handleInputChange}
+            className="h-4 w-4 text-indigo-600 focus:ring-indigo-500 border-gray-300 rounded"
+          />
+          <label htmlFor="tiered" className="ml-2 block text-sm text-gray-700">
+            Sell in rounds
+          </label>
+        </div>
+
+        {formData.tiered && (
+          <div className="space-y-4">
+            {rounds.map((round, index) => (
+              <div key={index} className="border border-gray-200 rounded-md p-3 space-y-2">
+                <div className="flex justify-between items-center">
+                  <span className="text-sm font-medium text-gray-700">Round {index + 1}</span>
+                  {rounds.length > 1 && (
+                    <button
+                      type="button"
+                      onClick={() => setRounds(rounds.filter((_, i) => i !== index))}
+                      className="text-sm text-red-600 hover:text-red-800"
+                    >
+                      Remove
+                    </button>
+                  )}
+                </div>
+                <input
+                  type="text"
+                  value={round.name}
+                  onChange={(e) => handleRoundChange(index, 'name', e.target.value)}
+                  placeholder="Name, e.g., seed"
+                  className="block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+                />
+                <input
+                  type="number"
+                  value={round.rate}
+                  onChange={(e) => handleRoundChange(index, 'rate', e.target.value)}
+                  placeholder="Rate (tokens per ETH)"
+                  className="block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+                />
+                <input
+                  type="number"
+                  step="0.01"
+                  value={round.cap}
+                  onChange={(e) => handleRoundChange(index, 'cap', e.target.value)}
+                  placeholder="Cap (ETH, optional)"
+                  className="block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+                />
+                <input
+                  type="datetime-local"
+                  value={round.startTime}
+                  onChange={(e) => handleRoundChange(index, 'startTime', e.target.value)}
+                  className="block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+                />
+                <input
+                  type="datetime-local"
+                  value={round.endTime}
+                  onChange={(e) => handleRoundChange(index, 'endTime', e.target.value)}
+                  className="block w-full border-gray-300 rounded-md shadow-sm focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm p-2 border"
+                />
+              </div>
+            ))}
+            <button
+              type="button"
+              onClick={() => setRounds([...rounds, emptyRound()])}
+              className="text-sm text-indigo-600 hover:text-indigo-800"
+            >
+              Add round
+            </button>
+            <p className="text-sm text-gray-500">
+              Rounds follow each other and sell at their own rate up to their cap; the presale opens with the first round
+            </p>
+          </div>
+        )}
+
         <button
           type="submit"
           disabled={loading || tokens.length === 0}
@@ -7,7 +7,17 @@ import {
   Token,
   Presale,
   PresaleData,
-  ParticipateResponse
+  PresaleStatus,
+  ParticipateResponse,
+  ParticipationQuote,
+  WhitelistEntry,
+  UploadWhitelistResponse,
+  WhitelistProof,
+  VestingSchedule,
+  VestingPosition,
+  ClaimResponse,
+  PresaleRoundRequest,
+  PaymentCurrency
 } from '@/types'
 
 const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api'
@@ -94,10 +104,32 @@ export class ApiClient {
   }
 
   // Presale methods
-  async createPresale(tokenAddress: string, rate: string, softCap: string, hardCap: string, deadline: string) {
+  async createPresale(tokenAddress: string, rate: string, softCap: string, hardCap: string, startTime: string, deadline: string, whitelisted = false, minContribution = '', maxContribution = '', vesting: VestingSchedule | null = null, rounds: PresaleRoundRequest[] = [], payment: PaymentCurrency | null = null) {
     return this.request<ApiResponse<CreatePresaleResponse>>('/presale/create', {
       method: 'POST',
-      body: JSON.stringify({ token_address: tokenAddress, rate, soft_cap: softCap, hard_cap: hardCap, deadline }),
+      body: JSON.stringify({ token_address: tokenAddress, rate, soft_cap: softCap, hard_cap: hardCap, start_time: startTime, deadline, whitelisted, min_contribution: minContribution, max_contribution: maxContribution, vesting, rounds, payment_token: payment?.token ?? '', payment_symbol: payment?.symbol ?? '', payment_decimals: payment?.decimals }),
+    })
+  }
+
+  async uploadWhitelist(id: number, entries: WhitelistEntry[]) {
+    return this.request<ApiResponse<UploadWhitelistResponse>>(`/presale/${id}/whitelist`, {
+      method: 'PUT',
+      body: JSON.stringify({ entries }),
+    })
+  }
+
+  async getWhitelistProof(id: number, address: string) {
+    return this.request<ApiResponse<WhitelistProof>>(`/public/presale/${id}/proof/${address}`)
+  }
+
+  async getVestingPosition(id: number, address: string) {
+    return this.request<ApiResponse<VestingPosition>>(`/public/presale/${id}/vesting/${address}`)
+  }
+
+  async claimVestedTokens(id: number, txHash: string) {
+    return this.request<ApiResponse<ClaimResponse>>(`/presale/${id}/claim`, {
+      method: 'POST',
+      body: JSON.stringify({ tx_hash: txHash }),
     })
   }
 
@@ -109,14 +141,27 @@ export class ApiClient {
     return this.request<ApiResponse<PresaleData>>(`/public/presale/${id}`)
   }
 
+  async getPresaleQuote(id: number, amount: string, from = '') {
+    const query = new URLSearchParams({ amount })
+    if (from) query.set('from', from)
+    return this.request<ApiResponse<ParticipationQuote>>(`/public/presale/${id}/quote?${query}`)
+  }
+
+  async transitionPresale(id: number, status: PresaleStatus, reason = '') {
+    return this.request<ApiResponse<Presale>>(`/presale/${id}/status`, {
+      method: 'POST',
+      body: JSON.stringify({ status, reason }),
+    })
+  }
+
   async listPresales() {
     return this.request<ApiResponse<Presale[]>>('/presale/list')
   }
 
-  async participateInPresale(id: number, amountETH: string, txHash: string) {
+  async participateInPresale(id: number, amount: string, txHash: string) {
     return this.request<ApiResponse<ParticipateResponse>>(`/presale/${id}/participate`, {
       method: 'POST',
-      body: JSON.stringify({ amount_eth: amountETH, tx_hash: txHash }),
+      body: JSON.stringify({ amount, tx_hash: txHash }),
     })
   }
 }
@@ -7,8 +7,22 @@ export interface Token {
   creator_address: string
   tx_hash: string
   created_at: string
+  decimals: number
+  total_supply_raw: string
+  total_supply_formatted: string
 }
 
+export type PresaleStatus =
+  | 'draft'
+  | 'unfunded'
+  | 'scheduled'
+  | 'live'
+  | 'filled'
+  | 'ended'
+  | 'finalized_success'
+  | 'finalized_failed'
+  | 'cancelled'
+
 export interface Presale {
   id: number
   address: string
@@ -17,21 +31,81 @@ export interface Presale {
   rate: string
   soft_cap: string
   hard_cap: string
+  min_contribution: string
+  max_contribution: string
+  start_time: string
   deadline: string
   tx_hash: string
-  active: boolean
-  finalized: boolean
+  status: PresaleStatus
+  whitelisted: boolean
+  merkle_root?: string
+  vesting: boolean
+  vesting_tge_bps: number
+  vesting_cliff_seconds: number
+  vesting_duration_seconds: number
+  payment_token?: string
+  payment_symbol: string
+  payment_decimals: number
   created_at: string
+  status_updated_at: string
+  raised: string
+  remaining: string
+  rate_formatted: string
+  soft_cap_formatted: string
+  hard_cap_formatted: string
+  min_contribution_formatted: string
+  max_contribution_formatted: string
+  raised_formatted: string
+  remaining_formatted: string
+  rounds?: PresaleRound[]
+}
+
+export type RoundStatus = 'upcoming' | 'active' | 'filled' | 'ended'
+
+export interface PresaleRound {
+  presale_id: number
+  index: number
+  name: string
+  rate: string
+  cap: string
+  start_time: string
+  end_time: string
+  status: RoundStatus
+  raised: string
+  contributors: number
+  progress_bps: number
+  cap_formatted: string
+  raised_formatted: string
+}
+
+export interface PresaleRoundRequest {
+  name: string
+  rate: string
+  cap: string
+  start_time: string
+  end_time: string
+}
+
+export interface PaymentCurrency {
+  token: string
+  symbol: string
+  decimals?: number // read from the token when omitted
 }
 
 export interface PresaleParticipation {
   id: number
   presale_id: number
   participant_address: string
-  amount_eth: string
+  amount_paid: string
   amount_tokens: string
   tx_hash: string
+  round_index?: number
   created_at: string
+  payment_token?: string
+  payment_symbol: string
+  payment_decimals: number
+  amount_paid_formatted: string
+  amount_tokens_formatted: string
 }
 
 export interface ApiResponse<T> {
@@ -72,4 +146,84 @@ export interface ParticipateResponse {
 export interface PresaleData {
   presale: Presale
   token: Token
-}
\ No newline at end of file
+}
+
+export interface ParticipationQuote {
+  presale_id: number
+  amount: string
+  payment_token?: string
+  payment_decimals: number
+  rate: string
+  token_decimals: number
+  tokens: string
+  tokens_formatted: string
+  remaining_capacity: string
+  remaining_capacity_formatted: string
+  exceeds_cap: boolean
+  allocation?: string
+  contributed: string
+  exceeds_allocation: boolean
+  below_min_contribution: boolean
+  exceeds_max_contribution: boolean
+  round?: PresaleRound
+  exceeds_round_cap: boolean
+  gas_estimate: number | null
+  gas_error?: string
+}
+
+export interface WhitelistEntry {
+  address: string
+  allocation: string
+}
+
+export interface UploadWhitelistResponse {
+  presale_id: number
+  merkle_root: string
+  entries: number
+}
+
+export interface WhitelistProof {
+  presale_id: number
+  address: string
+  allocation: string
+  allocation_formatted: string
+  merkle_root: string
+  proof: string[]
+}
+export interface VestingSchedule {
+  tge_bps: number
+  cliff_seconds: number
+  duration_seconds: number
+}
+
+export interface VestingPosition {
+  presale_id: number
+  address: string
+  purchased: string
+  vested: string
+  claimed: string
+  claimable: string
+  locked: string
+  vesting_start: string | null
+  vesting_end: string | null
+  purchased_formatted: string
+  vested_formatted: string
+  claimed_formatted: string
+  claimable_formatted: string
+  locked_formatted: string
+}
+
+export interface VestingClaim {
+  id: number
+  presale_id: number
+  participant_address: string
+  amount_tokens: string
+  tx_hash: string
+  created_at: string
+  amount_tokens_formatted: string
+}
+
+export interface ClaimResponse {
+  claim: VestingClaim
+  position: VestingPosition
+}
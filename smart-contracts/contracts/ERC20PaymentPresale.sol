@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "@openzeppelin/contracts/token/ERC20/utils/SafeERC20.sol";
+import "./Presale.sol";
+
+/**
+ * @title ERC20PaymentPresale
+ * @dev Presale paid in an ERC-20 (a stablecoin, say) instead of ETH. Buyers
+ * approve the amount to this contract and call buyTokens(amount); raised
+ * funds and refunds are paid out in the same token. The rate is in token
+ * base units per base unit of the payment token. Only standard ERC-20s are
+ * supported: a payment that arrives short of amount, as with fee-on-transfer
+ * tokens, reverts so the raise always matches what buyers paid.
+ */
+contract ERC20PaymentPresale is Presale {
+    using SafeERC20 for IERC20;
+    
+    IERC20 public paymentToken;
+    
+    constructor(
+        address _token,
+        uint256 _rate,
+        uint256 _softCap,
+        uint256 _hardCap,
+        uint256 _startTime,
+        uint256 _deadline,
+        address _owner,
+        address _paymentToken
+    ) Presale(_token, _rate, _softCap, _hardCap, _startTime, _deadline, _owner) {
+        require(_paymentToken != address(0), "Invalid payment token address");
+        require(_paymentToken != _token, "Payment token must differ from token");
+        
+        paymentToken = IERC20(_paymentToken);
+    }
+    
+    /**
+     * @dev ETH is not accepted; use buyTokens(amount)
+     */
+    function buyTokens() external payable override {
+        revert("Presale is paid in tokens");
+    }
+    
+    /**
+     * @dev Purchase tokens with amount of the payment token, approved to this
+     * contract beforehand
+     */
+    function buyTokens(uint256 amount) external presaleIsActive nonReentrant {
+        require(amount > 0, "Must pay tokens");
+        
+        uint256 balanceBefore = paymentToken.balanceOf(address(this));
+        paymentToken.safeTransferFrom(msg.sender, address(this), amount);
+        require(
+            paymentToken.balanceOf(address(this)) - balanceBefore == amount,
+            "Fee-on-transfer tokens not supported"
+        );
+        
+        _buyTokens(amount);
+    }
+    
+    /**
+     * @dev Pay out in the payment token
+     */
+    function _sendPayment(address to, uint256 amount) internal override {
+        paymentToken.safeTransfer(to, amount);
+    }
+    
+    /**
+     * @dev Payment tokens held by the contract
+     */
+    function _paymentBalance() internal view override returns (uint256) {
+        return paymentToken.balanceOf(address(this));
+    }
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./PresaleVariantFactory.sol";
+import "./ERC20PaymentPresale.sol";
+
+/**
+ * @title ERC20PaymentPresaleFactory
+ * @dev Deploys presales paid in an ERC-20 and registers them with the launchpad
+ */
+contract ERC20PaymentPresaleFactory is PresaleVariantFactory {
+    constructor(address _launchpad) PresaleVariantFactory(_launchpad) {}
+    
+    /**
+     * @dev Create a presale paid in paymentToken instead of ETH; rate is in token base
+     * units per base unit of the payment token
+     */
+    function createERC20PaymentPresale(
+        address tokenAddress,
+        uint256 rate,
+        uint256 softCap,
+        uint256 hardCap,
+        uint256 startTime,
+        uint256 deadline,
+        address paymentToken
+    ) external returns (address) {
+        require(tokenAddress != address(0), "Invalid token address");
+        
+        ERC20PaymentPresale newPresale = new ERC20PaymentPresale(
+            tokenAddress,
+            rate,
+            softCap,
+            hardCap,
+            startTime,
+            deadline,
+            msg.sender,
+            paymentToken
+        );
+        
+        return launchpad.registerPresale(LaunchpadFactory.PresaleInfo({
+            presaleAddress: address(newPresale),
+            tokenAddress: tokenAddress,
+            rate: rate,
+            softCap: softCap,
+            hardCap: hardCap,
+            startTime: startTime,
+            deadline: deadline,
+            creator: msg.sender,
+            createdAt: block.timestamp,
+            whitelisted: false,
+            paymentToken: paymentToken
+        }));
+    }
+}
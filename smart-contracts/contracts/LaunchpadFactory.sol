@@ -1,14 +1,19 @@
 // SPDX-License-Identifier: MIT
 pragma solidity ^0.8.20;
 
+import "@openzeppelin/contracts/access/Ownable.sol";
 import "./MyToken.sol";
 import "./Presale.sol";
 
 /**
  * @title LaunchpadFactory
- * @dev Factory contract for deploying tokens and presales
+ * @dev Factory contract for deploying tokens and presales, and the registry of
+ * every presale on the launchpad. Presale variants are deployed by their own
+ * factories (WhitelistPresaleFactory and so on), which the owner approves to
+ * register here, so no factory carries the bytecode of every variant past the
+ * EIP-170 contract size limit.
  */
-contract LaunchpadFactory {
+contract LaunchpadFactory is Ownable {
     struct TokenInfo {
         address tokenAddress;
         string name;
@@ -24,9 +29,12 @@ contract LaunchpadFactory {
         uint256 rate;
         uint256 softCap;
         uint256 hardCap;
+        uint256 startTime;
         uint256 deadline;
         address creator;
         uint256 createdAt;
+        bool whitelisted;
+        address paymentToken; // zero for ETH
     }
     
     TokenInfo[] public tokens;
@@ -36,6 +44,7 @@ contract LaunchpadFactory {
     mapping(address => uint256[]) public userPresales;
     mapping(address => uint256) public tokenToIndex;
     mapping(address => uint256) public presaleToIndex;
+    mapping(address => bool) public presaleFactories;
     
     event TokenCreated(
         address indexed tokenAddress,
@@ -51,10 +60,25 @@ contract LaunchpadFactory {
         uint256 rate,
         uint256 softCap,
         uint256 hardCap,
+        uint256 startTime,
         uint256 deadline,
         address indexed creator
     );
     
+    event PresaleFactorySet(address indexed factory, bool approved);
+    
+    constructor() Ownable(msg.sender) {}
+    
+    /**
+     * @dev Allow or stop a variant factory registering presales
+     */
+    function setPresaleFactory(address factory, bool approved) external onlyOwner {
+        require(factory != address(0), "Invalid factory address");
+        
+        presaleFactories[factory] = approved;
+        emit PresaleFactorySet(factory, approved);
+    }
+    
     /**
      * @dev Create a new ERC20 token
      */
@@ -86,13 +110,14 @@ contract LaunchpadFactory {
     }
     
     /**
-     * @dev Create a new presale
+     * @dev Create a new presale that opens at startTime (a past time opens it immediately)
      */
     function createPresale(
         address tokenAddress,
         uint256 rate,
         uint256 softCap,
         uint256 hardCap,
+        uint256 startTime,
         uint256 deadline
     ) external returns (address) {
         require(tokenAddress != address(0), "Invalid token address");
@@ -102,31 +127,57 @@ contract LaunchpadFactory {
             rate,
             softCap,
             hardCap,
+            startTime,
             deadline,
             msg.sender
         );
         
-        address presaleAddress = address(newPresale);
-        
-        PresaleInfo memory presaleInfo = PresaleInfo({
-            presaleAddress: presaleAddress,
+        return _registerPresale(PresaleInfo({
+            presaleAddress: address(newPresale),
             tokenAddress: tokenAddress,
             rate: rate,
             softCap: softCap,
             hardCap: hardCap,
+            startTime: startTime,
             deadline: deadline,
             creator: msg.sender,
-            createdAt: block.timestamp
-        });
+            createdAt: block.timestamp,
+            whitelisted: false,
+            paymentToken: address(0)
+        }));
+    }
+    
+    /**
+     * @dev Record a presale deployed by an approved variant factory for
+     * presaleInfo.creator
+     */
+    function registerPresale(PresaleInfo calldata presaleInfo) external returns (address) {
+        require(presaleFactories[msg.sender], "Not a presale factory");
         
+        return _registerPresale(presaleInfo);
+    }
+    
+    /**
+     * @dev Record a deployed presale of presaleInfo.creator
+     */
+    function _registerPresale(PresaleInfo memory presaleInfo) internal returns (address) {
         presales.push(presaleInfo);
         uint256 presaleIndex = presales.length - 1;
-        userPresales[msg.sender].push(presaleIndex);
-        presaleToIndex[presaleAddress] = presaleIndex;
+        userPresales[presaleInfo.creator].push(presaleIndex);
+        presaleToIndex[presaleInfo.presaleAddress] = presaleIndex;
         
-        emit PresaleCreated(presaleAddress, tokenAddress, rate, softCap, hardCap, deadline, msg.sender);
+        emit PresaleCreated(
+            presaleInfo.presaleAddress,
+            presaleInfo.tokenAddress,
+            presaleInfo.rate,
+            presaleInfo.softCap,
+            presaleInfo.hardCap,
+            presaleInfo.startTime,
+            presaleInfo.deadline,
+            presaleInfo.creator
+        );
         
-        return presaleAddress;
+        return presaleInfo.presaleAddress;
     }
     
     /**
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./Presale.sol";
+
+/**
+ * @title LimitedPresale
+ * @dev Presale with a minimum and maximum total contribution per wallet
+ * (0 for no limit)
+ */
+contract LimitedPresale is Presale {
+    uint256 public minContribution;
+    uint256 public maxContribution;
+    
+    constructor(
+        address _token,
+        uint256 _rate,
+        uint256 _softCap,
+        uint256 _hardCap,
+        uint256 _startTime,
+        uint256 _deadline,
+        address _owner,
+        uint256 _minContribution,
+        uint256 _maxContribution
+    ) Presale(_token, _rate, _softCap, _hardCap, _startTime, _deadline, _owner) {
+        require(_minContribution <= _hardCap, "Min contribution above hard cap");
+        require(_maxContribution <= _hardCap, "Max contribution above hard cap");
+        require(_maxContribution == 0 || _minContribution <= _maxContribution, "Min contribution above max");
+        
+        minContribution = _minContribution;
+        maxContribution = _maxContribution;
+    }
+    
+    /**
+     * @dev Purchase tokens with ETH within the sender's contribution limits
+     */
+    function buyTokens() external payable override presaleIsActive nonReentrant {
+        uint256 contributed = contributions[msg.sender] + msg.value;
+        require(contributed >= minContribution, "Below min contribution");
+        require(maxContribution == 0 || contributed <= maxContribution, "Exceeds max contribution");
+        
+        _buyTokens(msg.value);
+    }
+}
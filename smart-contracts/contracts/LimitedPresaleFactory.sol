@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./PresaleVariantFactory.sol";
+import "./LimitedPresale.sol";
+
+/**
+ * @title LimitedPresaleFactory
+ * @dev Deploys contribution-limited presales and registers them with the launchpad
+ */
+contract LimitedPresaleFactory is PresaleVariantFactory {
+    constructor(address _launchpad) PresaleVariantFactory(_launchpad) {}
+    
+    /**
+     * @dev Create a presale limiting the total contribution of each wallet to
+     * between minContribution and maxContribution (0 for no limit)
+     */
+    function createLimitedPresale(
+        address tokenAddress,
+        uint256 rate,
+        uint256 softCap,
+        uint256 hardCap,
+        uint256 startTime,
+        uint256 deadline,
+        uint256 minContribution,
+        uint256 maxContribution
+    ) external returns (address) {
+        require(tokenAddress != address(0), "Invalid token address");
+        
+        LimitedPresale newPresale = new LimitedPresale(
+            tokenAddress,
+            rate,
+            softCap,
+            hardCap,
+            startTime,
+            deadline,
+            msg.sender,
+            minContribution,
+            maxContribution
+        );
+        
+        return launchpad.registerPresale(LaunchpadFactory.PresaleInfo({
+            presaleAddress: address(newPresale),
+            tokenAddress: tokenAddress,
+            rate: rate,
+            softCap: softCap,
+            hardCap: hardCap,
+            startTime: startTime,
+            deadline: deadline,
+            creator: msg.sender,
+            createdAt: block.timestamp,
+            whitelisted: false,
+            paymentToken: address(0)
+        }));
+    }
+}
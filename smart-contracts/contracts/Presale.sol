@@ -12,9 +12,10 @@ import "@openzeppelin/contracts/utils/ReentrancyGuard.sol";
 contract Presale is Ownable, ReentrancyGuard {
     IERC20 public token;
     
-    uint256 public rate; // tokens per wei
+    uint256 public rate; // token base units per wei, or per base unit of the payment token
     uint256 public softCap;
     uint256 public hardCap;
+    uint256 public startTime; // contributions open at this timestamp
     uint256 public deadline;
     uint256 public raised;
     uint256 public tokensSold;
@@ -31,6 +32,7 @@ contract Presale is Ownable, ReentrancyGuard {
     
     modifier presaleIsActive() {
         require(presaleActive, "Presale is not active");
+        require(block.timestamp >= startTime, "Presale has not started");
         require(block.timestamp <= deadline, "Presale has ended");
         require(raised < hardCap, "Hard cap reached");
         _;
@@ -41,6 +43,7 @@ contract Presale is Ownable, ReentrancyGuard {
         uint256 _rate,
         uint256 _softCap,
         uint256 _hardCap,
+        uint256 _startTime,
         uint256 _deadline,
         address _owner
     ) Ownable(_owner) {
@@ -48,11 +51,13 @@ contract Presale is Ownable, ReentrancyGuard {
         require(_rate > 0, "Rate must be positive");
         require(_softCap < _hardCap, "Soft cap must be less than hard cap");
         require(_deadline > block.timestamp, "Deadline must be in the future");
+        require(_startTime < _deadline, "Start time must be before deadline");
         
         token = IERC20(_token);
         rate = _rate;
         softCap = _softCap;
         hardCap = _hardCap;
+        startTime = _startTime;
         deadline = _deadline;
         presaleActive = true;
     }
@@ -60,27 +65,49 @@ contract Presale is Ownable, ReentrancyGuard {
     /**
      * @dev Purchase tokens with ETH
      */
-    function buyTokens() external payable presaleIsActive nonReentrant {
-        require(msg.value > 0, "Must send ETH");
-        require(raised + msg.value <= hardCap, "Would exceed hard cap");
+    function buyTokens() external payable virtual presaleIsActive nonReentrant {
+        _buyTokens(msg.value);
+    }
+    
+    /**
+     * @dev Sell tokens to msg.sender for amount paid, in wei or base units of
+     * the payment token
+     */
+    function _buyTokens(uint256 amount) internal {
+        require(amount > 0, "Must send ETH");
+        require(raised + amount <= hardCap, "Would exceed hard cap");
         
-        uint256 tokens = msg.value * rate;
-        require(token.balanceOf(address(this)) >= tokens, "Not enough tokens in contract");
+        uint256 tokens = amount * _currentRate();
         
-        contributions[msg.sender] += msg.value;
+        contributions[msg.sender] += amount;
         tokensPurchased[msg.sender] += tokens;
-        raised += msg.value;
+        raised += amount;
         tokensSold += tokens;
         
-        require(token.transfer(msg.sender, tokens), "Token transfer failed");
+        _deliverTokens(msg.sender, tokens);
         
-        emit TokensPurchased(msg.sender, msg.value, tokens);
+        emit TokensPurchased(msg.sender, amount, tokens);
+    }
+    
+    /**
+     * @dev Tokens per wei of a purchase made now, rate by default
+     */
+    function _currentRate() internal view virtual returns (uint256) {
+        return rate;
+    }
+    
+    /**
+     * @dev Hand bought tokens to the buyer, at once by default
+     */
+    function _deliverTokens(address buyer, uint256 tokens) internal virtual {
+        require(token.balanceOf(address(this)) >= tokens, "Not enough tokens in contract");
+        require(token.transfer(buyer, tokens), "Token transfer failed");
     }
     
     /**
      * @dev Finalize the presale
      */
-    function finalizePresale() external onlyOwner {
+    function finalizePresale() public virtual onlyOwner {
         require(!presaleFinalized, "Presale already finalized");
         require(block.timestamp > deadline || raised >= hardCap, "Presale still active");
         
@@ -92,7 +119,7 @@ contract Presale is Ownable, ReentrancyGuard {
         
         if (successful) {
             // Transfer raised funds to owner
-            payable(owner()).transfer(raised);
+            _sendPayment(owner(), raised);
             emit FundsWithdrawn(owner(), raised);
         }
     }
@@ -104,10 +131,10 @@ contract Presale is Ownable, ReentrancyGuard {
         require(presaleFinalized, "Presale not finalized");
         require(raised >= softCap, "Soft cap not reached");
         
-        uint256 balance = address(this).balance;
+        uint256 balance = _paymentBalance();
         require(balance > 0, "No funds to withdraw");
         
-        payable(owner()).transfer(balance);
+        _sendPayment(owner(), balance);
         emit FundsWithdrawn(owner(), balance);
     }
     
@@ -122,13 +149,27 @@ contract Presale is Ownable, ReentrancyGuard {
         uint256 contribution = contributions[msg.sender];
         contributions[msg.sender] = 0;
         
-        payable(msg.sender).transfer(contribution);
+        _sendPayment(msg.sender, contribution);
+    }
+    
+    /**
+     * @dev Pay out raised funds or a refund, in ETH by default
+     */
+    function _sendPayment(address to, uint256 amount) internal virtual {
+        payable(to).transfer(amount);
+    }
+    
+    /**
+     * @dev Funds held by the contract, its ETH balance by default
+     */
+    function _paymentBalance() internal view virtual returns (uint256) {
+        return address(this).balance;
     }
     
     /**
      * @dev Emergency withdraw remaining tokens
      */
-    function withdrawRemainingTokens() external onlyOwner {
+    function withdrawRemainingTokens() external virtual onlyOwner {
         require(presaleFinalized, "Presale not finalized");
         
         uint256 remainingTokens = token.balanceOf(address(this));
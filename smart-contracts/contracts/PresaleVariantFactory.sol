@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./LaunchpadFactory.sol";
+
+/**
+ * @title PresaleVariantFactory
+ * @dev Base of the factories deploying one presale variant each. They register
+ * what they deploy with the launchpad, which must approve them first.
+ */
+abstract contract PresaleVariantFactory {
+    LaunchpadFactory public immutable launchpad;
+    
+    constructor(address _launchpad) {
+        require(_launchpad != address(0), "Invalid launchpad address");
+        
+        launchpad = LaunchpadFactory(_launchpad);
+    }
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./Presale.sol";
+
+/**
+ * @title TieredPresale
+ * @dev Presale sold in consecutive rounds (seed, private, public...), each
+ * with its own rate, cap (0 for up to the hard cap) and time window. The
+ * presale opens with the first round.
+ */
+contract TieredPresale is Presale {
+    struct Round {
+        uint256 rate; // tokens per wei
+        uint256 cap;
+        uint256 startTime;
+        uint256 endTime;
+    }
+    
+    Round[] public rounds;
+    mapping(uint256 => uint256) public roundRaised;
+    
+    constructor(
+        address _token,
+        uint256 _softCap,
+        uint256 _hardCap,
+        uint256 _deadline,
+        address _owner,
+        Round[] memory _rounds
+    ) Presale(_token, _firstRound(_rounds).rate, _softCap, _hardCap, _firstRound(_rounds).startTime, _deadline, _owner) {
+        for (uint256 i = 0; i < _rounds.length; i++) {
+            Round memory round = _rounds[i];
+            require(round.rate > 0, "Round rate must be positive");
+            require(round.cap <= _hardCap, "Round cap above hard cap");
+            require(round.startTime < round.endTime, "Round must start before it ends");
+            require(round.endTime <= _deadline, "Round must end by the deadline");
+            require(i == 0 || round.startTime >= _rounds[i - 1].endTime, "Rounds must not overlap");
+            
+            rounds.push(round);
+        }
+    }
+    
+    /**
+     * @dev Purchase tokens with ETH at the rate of the open round, within its cap
+     */
+    function buyTokens() external payable override presaleIsActive nonReentrant {
+        uint256 index = currentRound();
+        uint256 cap = rounds[index].cap;
+        require(cap == 0 || roundRaised[index] + msg.value <= cap, "Would exceed round cap");
+        
+        roundRaised[index] += msg.value;
+        _buyTokens(msg.value);
+    }
+    
+    /**
+     * @dev Index of the round open now
+     */
+    function currentRound() public view returns (uint256) {
+        for (uint256 i = 0; i < rounds.length; i++) {
+            if (block.timestamp >= rounds[i].startTime && block.timestamp < rounds[i].endTime) {
+                return i;
+            }
+        }
+        revert("No active round");
+    }
+    
+    /**
+     * @dev Get the number of rounds
+     */
+    function getRoundCount() external view returns (uint256) {
+        return rounds.length;
+    }
+    
+    /**
+     * @dev Sell at the rate of the open round
+     */
+    function _currentRate() internal view override returns (uint256) {
+        return rounds[currentRound()].rate;
+    }
+    
+    /**
+     * @dev First round, which sets the base rate and the start time
+     */
+    function _firstRound(Round[] memory _rounds) private pure returns (Round memory) {
+        require(_rounds.length > 0, "Presale needs a round");
+        return _rounds[0];
+    }
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./PresaleVariantFactory.sol";
+import "./TieredPresale.sol";
+
+/**
+ * @title TieredPresaleFactory
+ * @dev Deploys multi-round presales and registers them with the launchpad
+ */
+contract TieredPresaleFactory is PresaleVariantFactory {
+    constructor(address _launchpad) PresaleVariantFactory(_launchpad) {}
+    
+    /**
+     * @dev Create a presale sold in consecutive rounds with their own rates, caps and
+     * time windows; it opens with the first round
+     */
+    function createTieredPresale(
+        address tokenAddress,
+        uint256 softCap,
+        uint256 hardCap,
+        uint256 deadline,
+        TieredPresale.Round[] calldata rounds
+    ) external returns (address) {
+        require(tokenAddress != address(0), "Invalid token address");
+        
+        TieredPresale newPresale = new TieredPresale(
+            tokenAddress,
+            softCap,
+            hardCap,
+            deadline,
+            msg.sender,
+            rounds
+        );
+        
+        return launchpad.registerPresale(LaunchpadFactory.PresaleInfo({
+            presaleAddress: address(newPresale),
+            tokenAddress: tokenAddress,
+            rate: rounds[0].rate,
+            softCap: softCap,
+            hardCap: hardCap,
+            startTime: rounds[0].startTime,
+            deadline: deadline,
+            creator: msg.sender,
+            createdAt: block.timestamp,
+            whitelisted: false,
+            paymentToken: address(0)
+        }));
+    }
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./Presale.sol";
+
+/**
+ * @title VestingPresale
+ * @dev Presale that holds bought tokens and releases them from a successful
+ * finalization (TGE): tgeBps of each purchase at once, the rest linearly over
+ * vestingDuration after cliffDuration.
+ */
+contract VestingPresale is Presale {
+    uint256 public constant BPS = 10000;
+    
+    uint256 public tgeBps;
+    uint256 public cliffDuration;
+    uint256 public vestingDuration;
+    uint256 public tgeTime; // set by a successful finalization
+    uint256 public totalClaimed;
+    
+    mapping(address => uint256) public claimed;
+    
+    event TokensClaimed(address indexed beneficiary, uint256 amount);
+    
+    constructor(
+        address _token,
+        uint256 _rate,
+        uint256 _softCap,
+        uint256 _hardCap,
+        uint256 _startTime,
+        uint256 _deadline,
+        address _owner,
+        uint256 _tgeBps,
+        uint256 _cliffDuration,
+        uint256 _vestingDuration
+    ) Presale(_token, _rate, _softCap, _hardCap, _startTime, _deadline, _owner) {
+        require(_tgeBps < BPS, "TGE must release less than everything");
+        require(_cliffDuration + _vestingDuration > 0, "Vesting needs a cliff or a duration");
+        
+        tgeBps = _tgeBps;
+        cliffDuration = _cliffDuration;
+        vestingDuration = _vestingDuration;
+    }
+    
+    /**
+     * @dev Keep bought tokens in the contract until they are claimed
+     */
+    function _deliverTokens(address, uint256) internal view override {
+        require(token.balanceOf(address(this)) >= tokensSold, "Not enough tokens in contract");
+    }
+    
+    /**
+     * @dev Finalize the presale; a successful one starts vesting
+     */
+    function finalizePresale() public override onlyOwner {
+        super.finalizePresale();
+        
+        if (raised >= softCap) {
+            tgeTime = block.timestamp;
+        }
+    }
+    
+    /**
+     * @dev Tokens of an account vested so far
+     */
+    function vestedAmount(address account) public view returns (uint256) {
+        if (tgeTime == 0) {
+            return 0;
+        }
+        
+        uint256 total = tokensPurchased[account];
+        uint256 elapsed = block.timestamp - tgeTime;
+        if (elapsed >= cliffDuration + vestingDuration) {
+            return total;
+        }
+        
+        uint256 tgeAmount = (total * tgeBps) / BPS;
+        if (elapsed < cliffDuration) {
+            return tgeAmount;
+        }
+        
+        return tgeAmount + ((total - tgeAmount) * (elapsed - cliffDuration)) / vestingDuration;
+    }
+    
+    /**
+     * @dev Tokens an account has vested and not claimed yet
+     */
+    function claimable(address account) public view returns (uint256) {
+        return vestedAmount(account) - claimed[account];
+    }
+    
+    /**
+     * @dev Transfer the sender's claimable tokens
+     */
+    function claim() external nonReentrant {
+        uint256 amount = claimable(msg.sender);
+        require(amount > 0, "Nothing to claim");
+        
+        claimed[msg.sender] += amount;
+        totalClaimed += amount;
+        
+        require(token.transfer(msg.sender, amount), "Token transfer failed");
+        emit TokensClaimed(msg.sender, amount);
+    }
+    
+    /**
+     * @dev Withdraw the tokens not owed to buyers
+     */
+    function withdrawRemainingTokens() external override onlyOwner {
+        require(presaleFinalized, "Presale not finalized");
+        
+        uint256 owed = raised >= softCap ? tokensSold - totalClaimed : 0;
+        uint256 balance = token.balanceOf(address(this));
+        if (balance > owed) {
+            require(token.transfer(owner(), balance - owed), "Token transfer failed");
+        }
+    }
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./PresaleVariantFactory.sol";
+import "./VestingPresale.sol";
+
+/**
+ * @title VestingPresaleFactory
+ * @dev Deploys vesting presales and registers them with the launchpad
+ */
+contract VestingPresaleFactory is PresaleVariantFactory {
+    constructor(address _launchpad) PresaleVariantFactory(_launchpad) {}
+    
+    /**
+     * @dev Create a presale that vests bought tokens from its successful finalization:
+     * tgeBps at once, the rest linearly over vestingDuration after cliffDuration
+     */
+    function createVestingPresale(
+        address tokenAddress,
+        uint256 rate,
+        uint256 softCap,
+        uint256 hardCap,
+        uint256 startTime,
+        uint256 deadline,
+        uint256 tgeBps,
+        uint256 cliffDuration,
+        uint256 vestingDuration
+    ) external returns (address) {
+        require(tokenAddress != address(0), "Invalid token address");
+        
+        VestingPresale newPresale = new VestingPresale(
+            tokenAddress,
+            rate,
+            softCap,
+            hardCap,
+            startTime,
+            deadline,
+            msg.sender,
+            tgeBps,
+            cliffDuration,
+            vestingDuration
+        );
+        
+        return launchpad.registerPresale(LaunchpadFactory.PresaleInfo({
+            presaleAddress: address(newPresale),
+            tokenAddress: tokenAddress,
+            rate: rate,
+            softCap: softCap,
+            hardCap: hardCap,
+            startTime: startTime,
+            deadline: deadline,
+            creator: msg.sender,
+            createdAt: block.timestamp,
+            whitelisted: false,
+            paymentToken: address(0)
+        }));
+    }
+}
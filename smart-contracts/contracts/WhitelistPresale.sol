@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "@openzeppelin/contracts/utils/cryptography/MerkleProof.sol";
+import "./Presale.sol";
+
+/**
+ * @title WhitelistPresale
+ * @dev Presale open only to addresses in a Merkle tree. Each leaf is
+ * keccak256(bytes.concat(keccak256(abi.encode(account, allocation)))), where
+ * allocation is the wei the account may contribute in total (0 for no limit).
+ */
+contract WhitelistPresale is Presale {
+    bytes32 public merkleRoot;
+    
+    event MerkleRootUpdated(bytes32 merkleRoot);
+    
+    constructor(
+        address _token,
+        uint256 _rate,
+        uint256 _softCap,
+        uint256 _hardCap,
+        uint256 _startTime,
+        uint256 _deadline,
+        address _owner,
+        bytes32 _merkleRoot
+    ) Presale(_token, _rate, _softCap, _hardCap, _startTime, _deadline, _owner) {
+        merkleRoot = _merkleRoot;
+    }
+    
+    /**
+     * @dev Set the whitelist root; it cannot change once the presale has started with one
+     */
+    function setMerkleRoot(bytes32 _merkleRoot) external onlyOwner {
+        require(merkleRoot == bytes32(0) || block.timestamp < startTime, "Presale has started");
+        
+        merkleRoot = _merkleRoot;
+        emit MerkleRootUpdated(_merkleRoot);
+    }
+    
+    /**
+     * @dev Purchases need a whitelist proof
+     */
+    function buyTokens() external payable override {
+        revert("Whitelist proof required");
+    }
+    
+    /**
+     * @dev Purchase tokens with ETH, proving the sender's allocation
+     */
+    function buyTokens(uint256 allocation, bytes32[] calldata proof) external payable presaleIsActive nonReentrant {
+        require(merkleRoot != bytes32(0), "Whitelist not set");
+        
+        bytes32 leaf = keccak256(bytes.concat(keccak256(abi.encode(msg.sender, allocation))));
+        require(MerkleProof.verify(proof, merkleRoot, leaf), "Not whitelisted");
+        require(allocation == 0 || contributions[msg.sender] + msg.value <= allocation, "Exceeds allocation");
+        
+        _buyTokens(msg.value);
+    }
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./PresaleVariantFactory.sol";
+import "./WhitelistPresale.sol";
+
+/**
+ * @title WhitelistPresaleFactory
+ * @dev Deploys whitelisted presales and registers them with the launchpad
+ */
+contract WhitelistPresaleFactory is PresaleVariantFactory {
+    constructor(address _launchpad) PresaleVariantFactory(_launchpad) {}
+    
+    /**
+     * @dev Create a presale open only to the addresses under merkleRoot, which may be
+     * zero and set on the presale later
+     */
+    function createWhitelistPresale(
+        address tokenAddress,
+        uint256 rate,
+        uint256 softCap,
+        uint256 hardCap,
+        uint256 startTime,
+        uint256 deadline,
+        bytes32 merkleRoot
+    ) external returns (address) {
+        require(tokenAddress != address(0), "Invalid token address");
+        
+        WhitelistPresale newPresale = new WhitelistPresale(
+            tokenAddress,
+            rate,
+            softCap,
+            hardCap,
+            startTime,
+            deadline,
+            msg.sender,
+            merkleRoot
+        );
+        
+        return launchpad.registerPresale(LaunchpadFactory.PresaleInfo({
+            presaleAddress: address(newPresale),
+            tokenAddress: tokenAddress,
+            rate: rate,
+            softCap: softCap,
+            hardCap: hardCap,
+            startTime: startTime,
+            deadline: deadline,
+            creator: msg.sender,
+            createdAt: block.timestamp,
+            whitelisted: true,
+            paymentToken: address(0)
+        }));
+    }
+}
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "@openzeppelin/contracts/token/ERC20/ERC20.sol";
+
+/**
+ * @title FeeOnTransferToken
+ * @dev Test token burning a 1% fee from every transfer, so less than the
+ * amount sent arrives
+ */
+contract FeeOnTransferToken is ERC20 {
+    uint256 public constant FEE_BPS = 100;
+    
+    constructor(uint256 supply) ERC20("Fee Token", "FEE") {
+        _mint(msg.sender, supply);
+    }
+    
+    /**
+     * @dev Burn the fee from transfers between accounts
+     */
+    function _update(address from, address to, uint256 value) internal override {
+        if (from != address(0) && to != address(0)) {
+            uint256 fee = (value * FEE_BPS) / 10000;
+            super._update(from, address(0), fee);
+            value -= fee;
+        }
+        
+        super._update(from, to, value);
+    }
+}
@@ -14,6 +14,8 @@ module.exports = {
   networks: {
     hardhat: {
       chainId: 31337,
+      // Keep the EIP-170 size limit, so a factory that outgrows it fails here
+      allowUnlimitedContractSize: false,
       accounts: {
         count: 20,
         accountsBalance: "10000000000000000000000", // 10,000 ETH
@@ -13,10 +13,33 @@ async function main() {
   const factoryAddress = await factory.getAddress();
   console.log("LaunchpadFactory deployed to:", factoryAddress);
 
+  // Deploy the presale variant factories and approve them to register
+  // presales with the launchpad
+  const variants = [
+    "WhitelistPresaleFactory",
+    "LimitedPresaleFactory",
+    "VestingPresaleFactory",
+    "TieredPresaleFactory",
+    "ERC20PaymentPresaleFactory",
+  ];
+  const presaleFactories = {};
+  for (const name of variants) {
+    const Variant = await ethers.getContractFactory(name);
+    const variant = await Variant.deploy(factoryAddress);
+    await variant.waitForDeployment();
+
+    const variantAddress = await variant.getAddress();
+    await (await factory.setPresaleFactory(variantAddress, true)).wait();
+    console.log(`${name} deployed to:`, variantAddress);
+
+    presaleFactories[name] = variantAddress;
+  }
+
   // Save the deployment info
   const deploymentInfo = {
     network: network.name,
     factoryAddress: factoryAddress,
+    presaleFactories: presaleFactories,
     deployedAt: new Date().toISOString(),
     blockNumber: await ethers.provider.getBlockNumber(),
   };
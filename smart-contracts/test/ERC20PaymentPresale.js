@@ -0,0 +1,159 @@
+const {
+  time,
+  loadFixture,
+} = require("@nomicfoundation/hardhat-toolbox/network-helpers");
+const { expect } = require("chai");
+
+describe("ERC20PaymentPresale", function () {
+  const RATE = 2n; // token base units per base unit of the payment token
+  const SOFT_CAP = ethers.parseUnits("1000", 18);
+  const HARD_CAP = ethers.parseUnits("5000", 18);
+  const ONE_DAY_IN_SECS = 24 * 60 * 60;
+
+  // A presale paid in a standard ERC-20, with buyer holding 10,000 of it
+  async function deployERC20PaymentPresaleFixture() {
+    const [owner, buyer] = await ethers.getSigners();
+
+    const token = await ethers.deployContract("MyToken", ["Test", "TST", 1_000_000, owner.address]);
+    const paymentToken = await ethers.deployContract("MyToken", ["USD Coin", "USDC", 1_000_000, owner.address]);
+    await paymentToken.transfer(buyer.address, ethers.parseUnits("10000", 18));
+
+    const deadline = (await time.latest()) + ONE_DAY_IN_SECS;
+    const presale = await ethers.deployContract("ERC20PaymentPresale", [
+      token.target,
+      RATE,
+      SOFT_CAP,
+      HARD_CAP,
+      0,
+      deadline,
+      owner.address,
+      paymentToken.target,
+    ]);
+    await token.transfer(presale.target, HARD_CAP * RATE);
+
+    return { presale, token, paymentToken, deadline, owner, buyer };
+  }
+
+  // buy approves amount of the payment token and pays it into the presale
+  async function buy(presale, paymentToken, buyer, amount) {
+    await paymentToken.connect(buyer).approve(presale.target, amount);
+    return presale.connect(buyer)["buyTokens(uint256)"](amount);
+  }
+
+  describe("Deployment", function () {
+    it("Should fail if the payment token is the token sold", async function () {
+      const { token, deadline, owner } = await loadFixture(deployERC20PaymentPresaleFixture);
+
+      const ERC20PaymentPresale = await ethers.getContractFactory("ERC20PaymentPresale");
+      await expect(
+        ERC20PaymentPresale.deploy(
+          token.target,
+          RATE,
+          SOFT_CAP,
+          HARD_CAP,
+          0,
+          deadline,
+          owner.address,
+          token.target
+        )
+      ).to.be.revertedWith("Payment token must differ from token");
+    });
+  });
+
+  describe("Purchases", function () {
+    it("Should take the payment with transferFrom", async function () {
+      const { presale, token, paymentToken, buyer } = await loadFixture(
+        deployERC20PaymentPresaleFixture
+      );
+      const amount = ethers.parseUnits("500", 18);
+
+      await expect(buy(presale, paymentToken, buyer, amount)).to.changeTokenBalances(
+        paymentToken,
+        [buyer, presale],
+        [-amount, amount]
+      );
+
+      expect(await token.balanceOf(buyer.address)).to.equal(amount * RATE);
+      expect(await presale.contributions(buyer.address)).to.equal(amount);
+      expect(await presale.raised()).to.equal(amount);
+    });
+
+    it("Should fail without an allowance", async function () {
+      const { presale, paymentToken, buyer } = await loadFixture(
+        deployERC20PaymentPresaleFixture
+      );
+
+      await expect(
+        presale.connect(buyer)["buyTokens(uint256)"](ethers.parseUnits("500", 18))
+      ).to.be.revertedWithCustomError(paymentToken, "ERC20InsufficientAllowance");
+    });
+
+    it("Should reject ETH", async function () {
+      const { presale, buyer } = await loadFixture(deployERC20PaymentPresaleFixture);
+
+      await expect(
+        presale.connect(buyer)["buyTokens()"]({ value: ethers.parseEther("1") })
+      ).to.be.revertedWith("Presale is paid in tokens");
+    });
+
+    it("Should reject fee-on-transfer payment tokens", async function () {
+      const { token, deadline, owner, buyer } = await loadFixture(
+        deployERC20PaymentPresaleFixture
+      );
+
+      const feeToken = await ethers.deployContract("FeeOnTransferToken", [ethers.parseUnits("1000000", 18)]);
+      await feeToken.transfer(buyer.address, ethers.parseUnits("10000", 18));
+
+      const presale = await ethers.deployContract("ERC20PaymentPresale", [
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        0,
+        deadline,
+        owner.address,
+        feeToken.target,
+      ]);
+      await token.transfer(presale.target, HARD_CAP * RATE);
+
+      await expect(
+        buy(presale, feeToken, buyer, ethers.parseUnits("500", 18))
+      ).to.be.revertedWith("Fee-on-transfer tokens not supported");
+    });
+  });
+
+  describe("Finalization", function () {
+    it("Should pay the raise out in the payment token", async function () {
+      const { presale, paymentToken, deadline, owner, buyer } = await loadFixture(
+        deployERC20PaymentPresaleFixture
+      );
+      const amount = ethers.parseUnits("2000", 18);
+
+      await buy(presale, paymentToken, buyer, amount);
+      await time.increaseTo(deadline + 1);
+
+      await expect(presale.finalizePresale()).to.changeTokenBalances(
+        paymentToken,
+        [owner, presale],
+        [amount, -amount]
+      );
+    });
+
+    it("Should refund in the payment token when the soft cap is missed", async function () {
+      const { presale, paymentToken, deadline, buyer } = await loadFixture(
+        deployERC20PaymentPresaleFixture
+      );
+      const amount = ethers.parseUnits("500", 18);
+
+      await buy(presale, paymentToken, buyer, amount);
+      await time.increaseTo(deadline + 1);
+      await presale.finalizePresale();
+
+      await expect(presale.connect(buyer).getRefund()).to.changeTokenBalances(
+        paymentToken,
+        [buyer, presale],
+        [amount, -amount]
+      );
+    });
+  });
+});
@@ -0,0 +1,281 @@
+const {
+  time,
+  loadFixture,
+} = require("@nomicfoundation/hardhat-toolbox/network-helpers");
+const { anyValue } = require("@nomicfoundation/hardhat-chai-matchers/withArgs");
+const { expect } = require("chai");
+
+describe("LaunchpadFactory", function () {
+  const RATE = 1000n;
+  const SOFT_CAP = ethers.parseEther("2");
+  const HARD_CAP = ethers.parseEther("5");
+  const ONE_DAY_IN_SECS = 24 * 60 * 60;
+
+  // The launchpad with every variant factory approved, as scripts/deploy.js
+  // sets it up, and a token made through it by creator. Deploying on the
+  // Hardhat network enforces the EIP-170 contract size limit.
+  async function deployLaunchpadFixture() {
+    const [owner, creator, otherAccount] = await ethers.getSigners();
+
+    const launchpad = await ethers.deployContract("LaunchpadFactory");
+    const variants = {};
+    for (const name of [
+      "WhitelistPresaleFactory",
+      "LimitedPresaleFactory",
+      "VestingPresaleFactory",
+      "TieredPresaleFactory",
+      "ERC20PaymentPresaleFactory",
+    ]) {
+      variants[name] = await ethers.deployContract(name, [launchpad.target]);
+      await launchpad.setPresaleFactory(variants[name].target, true);
+    }
+
+    await launchpad.connect(creator).createToken("Test", "TST", 1_000_000);
+    const token = await ethers.getContractAt(
+      "MyToken",
+      (await launchpad.getTokenInfo(0)).tokenAddress
+    );
+
+    const startTime = (await time.latest()) + 60;
+    const deadline = startTime + ONE_DAY_IN_SECS;
+
+    return { launchpad, variants, token, startTime, deadline, owner, creator, otherAccount };
+  }
+
+  // expectRegistered checks the launchpad recorded presale 0 for creator and
+  // returns it as contractName
+  async function expectRegistered(launchpad, creator, contractName, whitelisted, paymentToken) {
+    const info = await launchpad.getPresaleInfo(0);
+    expect(info.creator).to.equal(creator.address);
+    expect(info.whitelisted).to.equal(whitelisted);
+    expect(info.paymentToken).to.equal(paymentToken);
+    expect([...(await launchpad.getUserPresales(creator.address))]).to.deep.equal([0n]);
+    expect(await launchpad.presaleToIndex(info.presaleAddress)).to.equal(0);
+
+    const presale = await ethers.getContractAt(contractName, info.presaleAddress);
+    expect(await presale.owner()).to.equal(creator.address);
+    return presale;
+  }
+
+  describe("Deployment", function () {
+    it("Should deploy every factory within the contract size limit", async function () {
+      const { launchpad, variants } = await loadFixture(deployLaunchpadFixture);
+
+      expect(await ethers.provider.getCode(launchpad.target)).to.not.equal("0x");
+      for (const variant of Object.values(variants)) {
+        expect(await ethers.provider.getCode(variant.target)).to.not.equal("0x");
+        expect(await variant.launchpad()).to.equal(launchpad.target);
+        expect(await launchpad.presaleFactories(variant.target)).to.equal(true);
+      }
+    });
+  });
+
+  describe("Tokens", function () {
+    it("Should create tokens owned by the caller", async function () {
+      const { launchpad, token, creator } = await loadFixture(deployLaunchpadFixture);
+
+      expect(await launchpad.getTokenCount()).to.equal(1);
+      expect([...(await launchpad.getUserTokens(creator.address))]).to.deep.equal([0n]);
+      expect(await token.owner()).to.equal(creator.address);
+      expect(await token.balanceOf(creator.address)).to.equal(ethers.parseUnits("1000000", 18));
+    });
+
+    it("Should emit an event on token creation", async function () {
+      const { launchpad, creator } = await loadFixture(deployLaunchpadFixture);
+
+      await expect(launchpad.connect(creator).createToken("Other", "OTH", 500))
+        .to.emit(launchpad, "TokenCreated")
+        .withArgs(anyValue, "Other", "OTH", 500, creator.address);
+    });
+  });
+
+  describe("Presales", function () {
+    it("Should create presales", async function () {
+      const { launchpad, token, startTime, deadline, creator } = await loadFixture(
+        deployLaunchpadFixture
+      );
+
+      await expect(
+        launchpad
+          .connect(creator)
+          .createPresale(token.target, RATE, SOFT_CAP, HARD_CAP, startTime, deadline)
+      )
+        .to.emit(launchpad, "PresaleCreated")
+        .withArgs(anyValue, token.target, RATE, SOFT_CAP, HARD_CAP, startTime, deadline, creator.address);
+
+      const presale = await expectRegistered(launchpad, creator, "Presale", false, ethers.ZeroAddress);
+      expect(await presale.rate()).to.equal(RATE);
+    });
+
+    it("Should create whitelisted presales", async function () {
+      const { launchpad, variants, token, startTime, deadline, creator } = await loadFixture(
+        deployLaunchpadFixture
+      );
+      const merkleRoot = ethers.keccak256(ethers.toUtf8Bytes("whitelist"));
+
+      await expect(
+        variants.WhitelistPresaleFactory.connect(creator).createWhitelistPresale(
+          token.target,
+          RATE,
+          SOFT_CAP,
+          HARD_CAP,
+          startTime,
+          deadline,
+          merkleRoot
+        )
+      )
+        .to.emit(launchpad, "PresaleCreated")
+        .withArgs(anyValue, token.target, RATE, SOFT_CAP, HARD_CAP, startTime, deadline, creator.address);
+
+      const presale = await expectRegistered(launchpad, creator, "WhitelistPresale", true, ethers.ZeroAddress);
+      expect(await presale.merkleRoot()).to.equal(merkleRoot);
+    });
+
+    it("Should create contribution-limited presales", async function () {
+      const { launchpad, variants, token, startTime, deadline, creator } = await loadFixture(
+        deployLaunchpadFixture
+      );
+      const min = ethers.parseEther("0.1");
+      const max = ethers.parseEther("1");
+
+      await variants.LimitedPresaleFactory.connect(creator).createLimitedPresale(
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        startTime,
+        deadline,
+        min,
+        max
+      );
+
+      const presale = await expectRegistered(launchpad, creator, "LimitedPresale", false, ethers.ZeroAddress);
+      expect(await presale.minContribution()).to.equal(min);
+      expect(await presale.maxContribution()).to.equal(max);
+    });
+
+    it("Should create vesting presales", async function () {
+      const { launchpad, variants, token, startTime, deadline, creator } = await loadFixture(
+        deployLaunchpadFixture
+      );
+
+      await variants.VestingPresaleFactory.connect(creator).createVestingPresale(
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        startTime,
+        deadline,
+        2500,
+        ONE_DAY_IN_SECS,
+        30 * ONE_DAY_IN_SECS
+      );
+
+      const presale = await expectRegistered(launchpad, creator, "VestingPresale", false, ethers.ZeroAddress);
+      expect(await presale.tgeBps()).to.equal(2500);
+      expect(await presale.cliffDuration()).to.equal(ONE_DAY_IN_SECS);
+      expect(await presale.vestingDuration()).to.equal(30 * ONE_DAY_IN_SECS);
+    });
+
+    it("Should create multi-round presales at the first round's rate and start", async function () {
+      const { launchpad, variants, token, startTime, deadline, creator } = await loadFixture(
+        deployLaunchpadFixture
+      );
+      const rounds = [
+        { rate: 2000n, cap: ethers.parseEther("1"), startTime, endTime: startTime + 60 * 60 },
+        { rate: 1000n, cap: 0n, startTime: startTime + 60 * 60, endTime: deadline },
+      ];
+
+      await expect(
+        variants.TieredPresaleFactory.connect(creator).createTieredPresale(
+          token.target,
+          SOFT_CAP,
+          HARD_CAP,
+          deadline,
+          rounds
+        )
+      )
+        .to.emit(launchpad, "PresaleCreated")
+        .withArgs(anyValue, token.target, 2000n, SOFT_CAP, HARD_CAP, startTime, deadline, creator.address);
+
+      const presale = await expectRegistered(launchpad, creator, "TieredPresale", false, ethers.ZeroAddress);
+      expect(await presale.getRoundCount()).to.equal(2);
+    });
+
+    it("Should create presales paid in an ERC-20", async function () {
+      const { launchpad, variants, token, startTime, deadline, owner, creator } = await loadFixture(
+        deployLaunchpadFixture
+      );
+      const paymentToken = await ethers.deployContract("MyToken", ["USD Coin", "USDC", 1_000_000, owner.address]);
+
+      await variants.ERC20PaymentPresaleFactory.connect(creator).createERC20PaymentPresale(
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        startTime,
+        deadline,
+        paymentToken.target
+      );
+
+      const presale = await expectRegistered(
+        launchpad,
+        creator,
+        "ERC20PaymentPresale",
+        false,
+        paymentToken.target
+      );
+      expect(await presale.paymentToken()).to.equal(paymentToken.target);
+    });
+  });
+
+  describe("Variant factories", function () {
+    it("Should only register presales from approved factories", async function () {
+      const { launchpad, token, startTime, deadline, otherAccount } = await loadFixture(
+        deployLaunchpadFixture
+      );
+
+      await expect(
+        launchpad.connect(otherAccount).registerPresale({
+          presaleAddress: otherAccount.address,
+          tokenAddress: token.target,
+          rate: RATE,
+          softCap: SOFT_CAP,
+          hardCap: HARD_CAP,
+          startTime,
+          deadline,
+          creator: otherAccount.address,
+          createdAt: startTime,
+          whitelisted: false,
+          paymentToken: ethers.ZeroAddress,
+        })
+      ).to.be.revertedWith("Not a presale factory");
+    });
+
+    it("Should stop a factory the owner revokes", async function () {
+      const { launchpad, variants, token, startTime, deadline, creator } = await loadFixture(
+        deployLaunchpadFixture
+      );
+      const factory = variants.LimitedPresaleFactory;
+
+      await expect(launchpad.setPresaleFactory(factory.target, false))
+        .to.emit(launchpad, "PresaleFactorySet")
+        .withArgs(factory.target, false);
+
+      await expect(
+        factory
+          .connect(creator)
+          .createLimitedPresale(token.target, RATE, SOFT_CAP, HARD_CAP, startTime, deadline, 0, 0)
+      ).to.be.revertedWith("Not a presale factory");
+      expect(await launchpad.getPresaleCount()).to.equal(0);
+    });
+
+    it("Should only let the owner approve factories", async function () {
+      const { launchpad, otherAccount } = await loadFixture(deployLaunchpadFixture);
+
+      await expect(launchpad.connect(otherAccount).setPresaleFactory(otherAccount.address, true))
+        .to.be.revertedWithCustomError(launchpad, "OwnableUnauthorizedAccount")
+        .withArgs(otherAccount.address);
+    });
+  });
+});
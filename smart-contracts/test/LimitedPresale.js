@@ -0,0 +1,128 @@
+const {
+  time,
+  loadFixture,
+} = require("@nomicfoundation/hardhat-toolbox/network-helpers");
+const { expect } = require("chai");
+
+describe("LimitedPresale", function () {
+  const RATE = 1000n;
+  const SOFT_CAP = ethers.parseEther("2");
+  const HARD_CAP = ethers.parseEther("5");
+  const MIN_CONTRIBUTION = ethers.parseEther("0.5");
+  const MAX_CONTRIBUTION = ethers.parseEther("2");
+  const ONE_DAY_IN_SECS = 24 * 60 * 60;
+
+  async function deployLimitedPresaleFixture() {
+    const [owner, buyer] = await ethers.getSigners();
+
+    const token = await ethers.deployContract("MyToken", ["Test", "TST", 1_000_000, owner.address]);
+    const deadline = (await time.latest()) + ONE_DAY_IN_SECS;
+    const presale = await ethers.deployContract("LimitedPresale", [
+      token.target,
+      RATE,
+      SOFT_CAP,
+      HARD_CAP,
+      0,
+      deadline,
+      owner.address,
+      MIN_CONTRIBUTION,
+      MAX_CONTRIBUTION,
+    ]);
+    await token.transfer(presale.target, HARD_CAP * RATE);
+
+    return { presale, token, deadline, owner, buyer };
+  }
+
+  describe("Deployment", function () {
+    it("Should fail if a limit is above the hard cap", async function () {
+      const { token, deadline, owner } = await loadFixture(deployLimitedPresaleFixture);
+
+      const LimitedPresale = await ethers.getContractFactory("LimitedPresale");
+      await expect(
+        LimitedPresale.deploy(
+          token.target,
+          RATE,
+          SOFT_CAP,
+          HARD_CAP,
+          0,
+          deadline,
+          owner.address,
+          0,
+          HARD_CAP + 1n
+        )
+      ).to.be.revertedWith("Max contribution above hard cap");
+    });
+
+    it("Should fail if the minimum is above the maximum", async function () {
+      const { token, deadline, owner } = await loadFixture(deployLimitedPresaleFixture);
+
+      const LimitedPresale = await ethers.getContractFactory("LimitedPresale");
+      await expect(
+        LimitedPresale.deploy(
+          token.target,
+          RATE,
+          SOFT_CAP,
+          HARD_CAP,
+          0,
+          deadline,
+          owner.address,
+          MAX_CONTRIBUTION,
+          MIN_CONTRIBUTION
+        )
+      ).to.be.revertedWith("Min contribution above max");
+    });
+  });
+
+  describe("Contribution limits", function () {
+    it("Should reject a first contribution below the minimum", async function () {
+      const { presale, buyer } = await loadFixture(deployLimitedPresaleFixture);
+
+      await expect(
+        presale.connect(buyer).buyTokens({ value: ethers.parseEther("0.1") })
+      ).to.be.revertedWith("Below min contribution");
+    });
+
+    it("Should count earlier contributions towards the minimum", async function () {
+      const { presale, buyer } = await loadFixture(deployLimitedPresaleFixture);
+
+      await presale.connect(buyer).buyTokens({ value: MIN_CONTRIBUTION });
+
+      await expect(
+        presale.connect(buyer).buyTokens({ value: ethers.parseEther("0.1") })
+      ).not.to.be.reverted;
+    });
+
+    it("Should reject contributions past the maximum", async function () {
+      const { presale, buyer } = await loadFixture(deployLimitedPresaleFixture);
+
+      await presale.connect(buyer).buyTokens({ value: ethers.parseEther("1.5") });
+
+      await expect(
+        presale.connect(buyer).buyTokens({ value: ethers.parseEther("1") })
+      ).to.be.revertedWith("Exceeds max contribution");
+      await expect(
+        presale.connect(buyer).buyTokens({ value: ethers.parseEther("0.5") })
+      ).not.to.be.reverted;
+      expect(await presale.contributions(buyer.address)).to.equal(MAX_CONTRIBUTION);
+    });
+
+    it("Should not limit the maximum when it is zero", async function () {
+      const { token, deadline, owner, buyer } = await loadFixture(deployLimitedPresaleFixture);
+
+      const presale = await ethers.deployContract("LimitedPresale", [
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        0,
+        deadline,
+        owner.address,
+        0,
+        0,
+      ]);
+      await token.transfer(presale.target, HARD_CAP * RATE);
+
+      await expect(presale.connect(buyer).buyTokens({ value: HARD_CAP })).not.to.be.reverted;
+    });
+  });
+});
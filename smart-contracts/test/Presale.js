@@ -0,0 +1,152 @@
+const {
+  time,
+  loadFixture,
+} = require("@nomicfoundation/hardhat-toolbox/network-helpers");
+const { expect } = require("chai");
+
+describe("Presale", function () {
+  const RATE = 1000n;
+  const SOFT_CAP = ethers.parseEther("2");
+  const HARD_CAP = ethers.parseEther("5");
+  const ONE_DAY_IN_SECS = 24 * 60 * 60;
+
+  // A presale open from deployment for a day, holding enough tokens to sell
+  // up to its hard cap
+  async function deployPresaleFixture() {
+    const [owner, buyer, otherAccount] = await ethers.getSigners();
+
+    const token = await ethers.deployContract("MyToken", ["Test", "TST", 1_000_000, owner.address]);
+    const deadline = (await time.latest()) + ONE_DAY_IN_SECS;
+    const presale = await ethers.deployContract("Presale", [
+      token.target,
+      RATE,
+      SOFT_CAP,
+      HARD_CAP,
+      0,
+      deadline,
+      owner.address,
+    ]);
+    await token.transfer(presale.target, HARD_CAP * RATE);
+
+    return { presale, token, deadline, owner, buyer, otherAccount };
+  }
+
+  describe("Deployment", function () {
+    it("Should set the caps, rate and owner", async function () {
+      const { presale, owner } = await loadFixture(deployPresaleFixture);
+
+      expect(await presale.rate()).to.equal(RATE);
+      expect(await presale.softCap()).to.equal(SOFT_CAP);
+      expect(await presale.hardCap()).to.equal(HARD_CAP);
+      expect(await presale.owner()).to.equal(owner.address);
+    });
+
+    it("Should fail if the soft cap is not below the hard cap", async function () {
+      const { token, deadline, owner } = await loadFixture(deployPresaleFixture);
+
+      const Presale = await ethers.getContractFactory("Presale");
+      await expect(
+        Presale.deploy(token.target, RATE, HARD_CAP, HARD_CAP, 0, deadline, owner.address)
+      ).to.be.revertedWith("Soft cap must be less than hard cap");
+    });
+  });
+
+  describe("Purchases", function () {
+    it("Should sell tokens at the rate", async function () {
+      const { presale, token, buyer } = await loadFixture(deployPresaleFixture);
+      const amount = ethers.parseEther("1");
+
+      await expect(presale.connect(buyer).buyTokens({ value: amount }))
+        .to.emit(presale, "TokensPurchased")
+        .withArgs(buyer.address, amount, amount * RATE);
+
+      expect(await token.balanceOf(buyer.address)).to.equal(amount * RATE);
+      expect(await presale.contributions(buyer.address)).to.equal(amount);
+      expect(await presale.raised()).to.equal(amount);
+    });
+
+    it("Should not sell past the hard cap", async function () {
+      const { presale, buyer, otherAccount } = await loadFixture(deployPresaleFixture);
+
+      await presale.connect(buyer).buyTokens({ value: ethers.parseEther("4") });
+
+      await expect(
+        presale.connect(otherAccount).buyTokens({ value: ethers.parseEther("2") })
+      ).to.be.revertedWith("Would exceed hard cap");
+    });
+
+    it("Should not sell before the start time", async function () {
+      const { token, deadline, owner, buyer } = await loadFixture(deployPresaleFixture);
+
+      const startTime = (await time.latest()) + 60 * 60;
+      const presale = await ethers.deployContract("Presale", [
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        startTime,
+        deadline,
+        owner.address,
+      ]);
+
+      await expect(
+        presale.connect(buyer).buyTokens({ value: ethers.parseEther("1") })
+      ).to.be.revertedWith("Presale has not started");
+    });
+
+    it("Should not sell after the deadline", async function () {
+      const { presale, deadline, buyer } = await loadFixture(deployPresaleFixture);
+
+      await time.increaseTo(deadline + 1);
+
+      await expect(
+        presale.connect(buyer).buyTokens({ value: ethers.parseEther("1") })
+      ).to.be.revertedWith("Presale has ended");
+    });
+  });
+
+  describe("Finalization", function () {
+    it("Should pay the raise to the owner when the soft cap is reached", async function () {
+      const { presale, deadline, owner, buyer } = await loadFixture(deployPresaleFixture);
+      const amount = ethers.parseEther("3");
+
+      await presale.connect(buyer).buyTokens({ value: amount });
+      await time.increaseTo(deadline + 1);
+
+      await expect(presale.finalizePresale()).to.changeEtherBalances(
+        [owner, presale],
+        [amount, -amount]
+      );
+      await expect(presale.connect(buyer).getRefund()).to.be.revertedWith(
+        "Presale was successful"
+      );
+    });
+
+    it("Should refund buyers when the soft cap is missed", async function () {
+      const { presale, deadline, buyer } = await loadFixture(deployPresaleFixture);
+      const amount = ethers.parseEther("1");
+
+      await presale.connect(buyer).buyTokens({ value: amount });
+      await time.increaseTo(deadline + 1);
+      await presale.finalizePresale();
+
+      await expect(presale.connect(buyer).getRefund()).to.changeEtherBalances(
+        [buyer, presale],
+        [amount, -amount]
+      );
+      await expect(presale.connect(buyer).getRefund()).to.be.revertedWith(
+        "No contribution found"
+      );
+    });
+
+    it("Should only let the owner finalize", async function () {
+      const { presale, deadline, otherAccount } = await loadFixture(deployPresaleFixture);
+
+      await time.increaseTo(deadline + 1);
+
+      await expect(presale.connect(otherAccount).finalizePresale())
+        .to.be.revertedWithCustomError(presale, "OwnableUnauthorizedAccount")
+        .withArgs(otherAccount.address);
+    });
+  });
+});
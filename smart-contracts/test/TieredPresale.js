@@ -0,0 +1,112 @@
+const {
+  time,
+  loadFixture,
+} = require("@nomicfoundation/hardhat-toolbox/network-helpers");
+const { expect } = require("chai");
+
+describe("TieredPresale", function () {
+  const SOFT_CAP = ethers.parseEther("1");
+  const HARD_CAP = ethers.parseEther("5");
+  const SEED_CAP = ethers.parseEther("1");
+  const ONE_DAY_IN_SECS = 24 * 60 * 60;
+
+  // A seed round at 2000 tokens per wei capped at SEED_CAP for the first day,
+  // then a day's gap and an uncapped public round at 1000 tokens per wei
+  async function deployTieredPresaleFixture() {
+    const [owner, buyer, otherAccount] = await ethers.getSigners();
+
+    const start = await time.latest();
+    const rounds = [
+      { rate: 2000n, cap: SEED_CAP, startTime: start, endTime: start + ONE_DAY_IN_SECS },
+      { rate: 1000n, cap: 0n, startTime: start + 2 * ONE_DAY_IN_SECS, endTime: start + 3 * ONE_DAY_IN_SECS },
+    ];
+    const deadline = start + 4 * ONE_DAY_IN_SECS;
+
+    const token = await ethers.deployContract("MyToken", ["Test", "TST", 1_000_000, owner.address]);
+    const presale = await ethers.deployContract("TieredPresale", [
+      token.target,
+      SOFT_CAP,
+      HARD_CAP,
+      deadline,
+      owner.address,
+      rounds,
+    ]);
+    await token.transfer(presale.target, HARD_CAP * 2000n);
+
+    return { presale, token, rounds, deadline, owner, buyer, otherAccount };
+  }
+
+  describe("Deployment", function () {
+    it("Should open with the first round", async function () {
+      const { presale, rounds } = await loadFixture(deployTieredPresaleFixture);
+
+      expect(await presale.getRoundCount()).to.equal(2);
+      expect(await presale.rate()).to.equal(rounds[0].rate);
+      expect(await presale.startTime()).to.equal(rounds[0].startTime);
+    });
+
+    it("Should fail without rounds", async function () {
+      const { token, deadline, owner } = await loadFixture(deployTieredPresaleFixture);
+
+      const TieredPresale = await ethers.getContractFactory("TieredPresale");
+      await expect(
+        TieredPresale.deploy(token.target, SOFT_CAP, HARD_CAP, deadline, owner.address, [])
+      ).to.be.revertedWith("Presale needs a round");
+    });
+
+    it("Should fail if rounds overlap", async function () {
+      const { token, rounds, deadline, owner } = await loadFixture(deployTieredPresaleFixture);
+
+      const overlapping = [rounds[0], { ...rounds[1], startTime: rounds[0].endTime - 1 }];
+      const TieredPresale = await ethers.getContractFactory("TieredPresale");
+      await expect(
+        TieredPresale.deploy(token.target, SOFT_CAP, HARD_CAP, deadline, owner.address, overlapping)
+      ).to.be.revertedWith("Rounds must not overlap");
+    });
+  });
+
+  describe("Tier rates", function () {
+    it("Should sell at the rate of the open round", async function () {
+      const { presale, token, buyer } = await loadFixture(deployTieredPresaleFixture);
+      const amount = ethers.parseEther("0.5");
+
+      await presale.connect(buyer).buyTokens({ value: amount });
+
+      expect(await presale.currentRound()).to.equal(0);
+      expect(await token.balanceOf(buyer.address)).to.equal(amount * 2000n);
+      expect(await presale.roundRaised(0)).to.equal(amount);
+    });
+
+    it("Should cap a round", async function () {
+      const { presale, buyer, otherAccount } = await loadFixture(deployTieredPresaleFixture);
+
+      await presale.connect(buyer).buyTokens({ value: ethers.parseEther("0.8") });
+
+      await expect(
+        presale.connect(otherAccount).buyTokens({ value: ethers.parseEther("0.5") })
+      ).to.be.revertedWith("Would exceed round cap");
+    });
+
+    it("Should reject purchases between rounds", async function () {
+      const { presale, rounds, buyer } = await loadFixture(deployTieredPresaleFixture);
+
+      await time.increaseTo(rounds[0].endTime);
+
+      await expect(
+        presale.connect(buyer).buyTokens({ value: ethers.parseEther("1") })
+      ).to.be.revertedWith("No active round");
+    });
+
+    it("Should sell at the next round's rate once it opens", async function () {
+      const { presale, token, rounds, buyer } = await loadFixture(deployTieredPresaleFixture);
+      const amount = ethers.parseEther("2");
+
+      await time.increaseTo(rounds[1].startTime);
+      await presale.connect(buyer).buyTokens({ value: amount });
+
+      expect(await presale.currentRound()).to.equal(1);
+      expect(await token.balanceOf(buyer.address)).to.equal(amount * 1000n);
+      expect(await presale.roundRaised(1)).to.equal(amount);
+    });
+  });
+});
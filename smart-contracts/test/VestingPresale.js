@@ -0,0 +1,151 @@
+const {
+  time,
+  loadFixture,
+} = require("@nomicfoundation/hardhat-toolbox/network-helpers");
+const { expect } = require("chai");
+
+describe("VestingPresale", function () {
+  const RATE = 1000n;
+  const SOFT_CAP = ethers.parseEther("2");
+  const HARD_CAP = ethers.parseEther("5");
+  const ONE_DAY_IN_SECS = 24 * 60 * 60;
+  const TGE_BPS = 2000n; // 20% at the TGE
+  const CLIFF = 30 * ONE_DAY_IN_SECS;
+  const DURATION = 100 * ONE_DAY_IN_SECS;
+
+  async function deployVestingPresaleFixture() {
+    const [owner, buyer] = await ethers.getSigners();
+
+    const token = await ethers.deployContract("MyToken", ["Test", "TST", 1_000_000, owner.address]);
+    const deadline = (await time.latest()) + ONE_DAY_IN_SECS;
+    const presale = await ethers.deployContract("VestingPresale", [
+      token.target,
+      RATE,
+      SOFT_CAP,
+      HARD_CAP,
+      0,
+      deadline,
+      owner.address,
+      TGE_BPS,
+      CLIFF,
+      DURATION,
+    ]);
+    await token.transfer(presale.target, HARD_CAP * RATE);
+
+    return { presale, token, deadline, owner, buyer };
+  }
+
+  // The fixture after buyer bought the soft cap and the presale was finalized
+  async function finalizedVestingPresaleFixture() {
+    const fixture = await deployVestingPresaleFixture();
+    const { presale, deadline, buyer } = fixture;
+
+    await presale.connect(buyer).buyTokens({ value: SOFT_CAP });
+    await time.increaseTo(deadline + 1);
+    await presale.finalizePresale();
+
+    const bought = SOFT_CAP * RATE;
+    const tgeAmount = (bought * TGE_BPS) / 10000n;
+    const tgeTime = await presale.tgeTime();
+
+    return { ...fixture, bought, tgeAmount, tgeTime };
+  }
+
+  describe("Deployment", function () {
+    it("Should fail if the TGE releases everything", async function () {
+      const { token, deadline, owner } = await loadFixture(deployVestingPresaleFixture);
+
+      const VestingPresale = await ethers.getContractFactory("VestingPresale");
+      await expect(
+        VestingPresale.deploy(
+          token.target,
+          RATE,
+          SOFT_CAP,
+          HARD_CAP,
+          0,
+          deadline,
+          owner.address,
+          10000,
+          CLIFF,
+          DURATION
+        )
+      ).to.be.revertedWith("TGE must release less than everything");
+    });
+  });
+
+  describe("Vesting schedule", function () {
+    it("Should hold bought tokens until the TGE", async function () {
+      const { presale, token, buyer } = await loadFixture(deployVestingPresaleFixture);
+
+      await presale.connect(buyer).buyTokens({ value: ethers.parseEther("1") });
+
+      expect(await token.balanceOf(buyer.address)).to.equal(0);
+      expect(await presale.tokensPurchased(buyer.address)).to.equal(ethers.parseEther("1") * RATE);
+      expect(await presale.vestedAmount(buyer.address)).to.equal(0);
+      await expect(presale.connect(buyer).claim()).to.be.revertedWith("Nothing to claim");
+    });
+
+    it("Should release the TGE share at finalization and nothing more during the cliff", async function () {
+      const { presale, token, buyer, tgeAmount, tgeTime } = await loadFixture(
+        finalizedVestingPresaleFixture
+      );
+
+      expect(tgeTime).to.be.greaterThan(0);
+      expect(await presale.vestedAmount(buyer.address)).to.equal(tgeAmount);
+
+      await expect(presale.connect(buyer).claim())
+        .to.emit(presale, "TokensClaimed")
+        .withArgs(buyer.address, tgeAmount);
+      expect(await token.balanceOf(buyer.address)).to.equal(tgeAmount);
+
+      await time.increaseTo(tgeTime + BigInt(CLIFF) - 10n);
+      await expect(presale.connect(buyer).claim()).to.be.revertedWith("Nothing to claim");
+    });
+
+    it("Should vest the rest linearly after the cliff", async function () {
+      const { presale, token, buyer, bought, tgeAmount, tgeTime } = await loadFixture(
+        finalizedVestingPresaleFixture
+      );
+
+      // Halfway through the vesting duration
+      await time.setNextBlockTimestamp(tgeTime + BigInt(CLIFF + DURATION / 2));
+      await expect(presale.connect(buyer).claim()).to.changeTokenBalances(
+        token,
+        [buyer, presale],
+        [tgeAmount + (bought - tgeAmount) / 2n, -(tgeAmount + (bought - tgeAmount) / 2n)]
+      );
+
+      await time.setNextBlockTimestamp(tgeTime + BigInt(CLIFF + DURATION));
+      await presale.connect(buyer).claim();
+
+      expect(await token.balanceOf(buyer.address)).to.equal(bought);
+      expect(await presale.claimed(buyer.address)).to.equal(bought);
+      await expect(presale.connect(buyer).claim()).to.be.revertedWith("Nothing to claim");
+    });
+
+    it("Should not vest when the soft cap is missed", async function () {
+      const { presale, deadline, buyer } = await loadFixture(deployVestingPresaleFixture);
+      const amount = ethers.parseEther("1");
+
+      await presale.connect(buyer).buyTokens({ value: amount });
+      await time.increaseTo(deadline + 1);
+      await presale.finalizePresale();
+
+      expect(await presale.tgeTime()).to.equal(0);
+      expect(await presale.vestedAmount(buyer.address)).to.equal(0);
+      await expect(presale.connect(buyer).getRefund()).to.changeEtherBalance(buyer, amount);
+    });
+
+    it("Should only withdraw the tokens not owed to buyers", async function () {
+      const { presale, token, owner, bought } = await loadFixture(finalizedVestingPresaleFixture);
+      const unsold = HARD_CAP * RATE - bought;
+
+      await expect(presale.withdrawRemainingTokens()).to.changeTokenBalances(
+        token,
+        [owner, presale],
+        [unsold, -unsold]
+      );
+      expect(await token.balanceOf(presale.target)).to.equal(bought);
+    });
+  });
+});
@@ -0,0 +1,201 @@
+const {
+  time,
+  loadFixture,
+} = require("@nomicfoundation/hardhat-toolbox/network-helpers");
+const { expect } = require("chai");
+
+// leaf hashes an allocation the way WhitelistPresale and the backend do:
+// keccak256(bytes.concat(keccak256(abi.encode(account, allocation))))
+function leaf(account, allocation) {
+  const encoded = ethers.AbiCoder.defaultAbiCoder().encode(
+    ["address", "uint256"],
+    [account, allocation]
+  );
+  return ethers.keccak256(ethers.concat([ethers.keccak256(encoded)]));
+}
+
+// hashPair hashes two nodes in sorted order, as MerkleProof expects
+function hashPair(a, b) {
+  return BigInt(a) < BigInt(b)
+    ? ethers.keccak256(ethers.concat([a, b]))
+    : ethers.keccak256(ethers.concat([b, a]));
+}
+
+describe("WhitelistPresale", function () {
+  const RATE = 1000n;
+  const SOFT_CAP = ethers.parseEther("2");
+  const HARD_CAP = ethers.parseEther("10");
+  const ALLOCATION = ethers.parseEther("2");
+  const ONE_DAY_IN_SECS = 24 * 60 * 60;
+
+  // A whitelist of buyer, allowed ALLOCATION, and unlimitedBuyer, with no
+  // limit (allocation 0)
+  async function deployWhitelistPresaleFixture() {
+    const [owner, buyer, unlimitedBuyer, otherAccount] = await ethers.getSigners();
+
+    const buyerLeaf = leaf(buyer.address, ALLOCATION);
+    const unlimitedLeaf = leaf(unlimitedBuyer.address, 0);
+    const merkleRoot = hashPair(buyerLeaf, unlimitedLeaf);
+
+    const token = await ethers.deployContract("MyToken", ["Test", "TST", 1_000_000, owner.address]);
+    const deadline = (await time.latest()) + ONE_DAY_IN_SECS;
+    const presale = await ethers.deployContract("WhitelistPresale", [
+      token.target,
+      RATE,
+      SOFT_CAP,
+      HARD_CAP,
+      0,
+      deadline,
+      owner.address,
+      merkleRoot,
+    ]);
+    await token.transfer(presale.target, HARD_CAP * RATE);
+
+    return {
+      presale,
+      token,
+      deadline,
+      merkleRoot,
+      buyerProof: [unlimitedLeaf],
+      unlimitedProof: [buyerLeaf],
+      owner,
+      buyer,
+      unlimitedBuyer,
+      otherAccount,
+    };
+  }
+
+  describe("Leaves", function () {
+    it("Should match the StandardMerkleTree root the backend builds", async function () {
+      // The values and root of the @openzeppelin/merkle-tree README
+      const first = leaf("0x1111111111111111111111111111111111111111", ethers.parseEther("5"));
+      const second = leaf("0x2222222222222222222222222222222222222222", ethers.parseEther("2.5"));
+
+      expect(hashPair(first, second)).to.equal(
+        "0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77"
+      );
+    });
+  });
+
+  describe("Purchases", function () {
+    it("Should sell to a whitelisted address within its allocation", async function () {
+      const { presale, token, buyer, buyerProof } = await loadFixture(
+        deployWhitelistPresaleFixture
+      );
+      const amount = ethers.parseEther("1.5");
+
+      await presale
+        .connect(buyer)
+        ["buyTokens(uint256,bytes32[])"](ALLOCATION, buyerProof, { value: amount });
+
+      expect(await token.balanceOf(buyer.address)).to.equal(amount * RATE);
+    });
+
+    it("Should reject contributions past the allocation", async function () {
+      const { presale, buyer, buyerProof } = await loadFixture(deployWhitelistPresaleFixture);
+      const buy = (value) =>
+        presale.connect(buyer)["buyTokens(uint256,bytes32[])"](ALLOCATION, buyerProof, { value });
+
+      await buy(ethers.parseEther("1.5"));
+
+      await expect(buy(ethers.parseEther("1"))).to.be.revertedWith("Exceeds allocation");
+      await expect(buy(ethers.parseEther("0.5"))).not.to.be.reverted;
+    });
+
+    it("Should not limit a zero allocation", async function () {
+      const { presale, unlimitedBuyer, unlimitedProof } = await loadFixture(
+        deployWhitelistPresaleFixture
+      );
+
+      await expect(
+        presale
+          .connect(unlimitedBuyer)
+          ["buyTokens(uint256,bytes32[])"](0, unlimitedProof, { value: ethers.parseEther("5") })
+      ).not.to.be.reverted;
+    });
+
+    it("Should reject a proof claiming another allocation", async function () {
+      const { presale, buyer, buyerProof } = await loadFixture(deployWhitelistPresaleFixture);
+
+      await expect(
+        presale
+          .connect(buyer)
+          ["buyTokens(uint256,bytes32[])"](ethers.parseEther("10"), buyerProof, {
+            value: ethers.parseEther("3"),
+          })
+      ).to.be.revertedWith("Not whitelisted");
+    });
+
+    it("Should reject addresses outside the whitelist", async function () {
+      const { presale, otherAccount, buyerProof } = await loadFixture(
+        deployWhitelistPresaleFixture
+      );
+
+      await expect(
+        presale
+          .connect(otherAccount)
+          ["buyTokens(uint256,bytes32[])"](ALLOCATION, buyerProof, { value: ethers.parseEther("1") })
+      ).to.be.revertedWith("Not whitelisted");
+    });
+
+    it("Should require a proof", async function () {
+      const { presale, buyer } = await loadFixture(deployWhitelistPresaleFixture);
+
+      await expect(
+        presale.connect(buyer)["buyTokens()"]({ value: ethers.parseEther("1") })
+      ).to.be.revertedWith("Whitelist proof required");
+    });
+  });
+
+  describe("Merkle root", function () {
+    it("Should reject purchases until a root is set", async function () {
+      const { token, deadline, owner, buyer } = await loadFixture(deployWhitelistPresaleFixture);
+
+      const presale = await ethers.deployContract("WhitelistPresale", [
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        0,
+        deadline,
+        owner.address,
+        ethers.ZeroHash,
+      ]);
+
+      await expect(
+        presale
+          .connect(buyer)
+          ["buyTokens(uint256,bytes32[])"](ALLOCATION, [], { value: ethers.parseEther("1") })
+      ).to.be.revertedWith("Whitelist not set");
+    });
+
+    it("Should let the owner set a missing root after the start", async function () {
+      const { token, deadline, merkleRoot, owner } = await loadFixture(
+        deployWhitelistPresaleFixture
+      );
+
+      const presale = await ethers.deployContract("WhitelistPresale", [
+        token.target,
+        RATE,
+        SOFT_CAP,
+        HARD_CAP,
+        0,
+        deadline,
+        owner.address,
+        ethers.ZeroHash,
+      ]);
+
+      await expect(presale.setMerkleRoot(merkleRoot))
+        .to.emit(presale, "MerkleRootUpdated")
+        .withArgs(merkleRoot);
+    });
+
+    it("Should lock the root once the presale has started", async function () {
+      const { presale } = await loadFixture(deployWhitelistPresaleFixture);
+
+      await expect(presale.setMerkleRoot(ethers.ZeroHash)).to.be.revertedWith(
+        "Presale has started"
+      );
+    });
+  });
+});